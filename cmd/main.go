@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 
 	"github.com/brucexc/pray-to-earn/internal/config"
@@ -36,6 +37,23 @@ var command = cobra.Command{
 	},
 }
 
+// stubVerifierCommand runs a local siteverify endpoint for the stub human verification provider.
+var stubVerifierCommand = cobra.Command{
+	Use:   "stub-verifier",
+	Short: "Serve a local siteverify endpoint that accepts a single token",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		address, _ := cmd.Flags().GetString("address")
+		token, _ := cmd.Flags().GetString("token")
+
+		mux := http.NewServeMux()
+		mux.Handle("/siteverify", &hub.StubVerifyServer{Token: token})
+
+		zap.L().Info("serving stub verifier", zap.String("address", address))
+
+		return http.ListenAndServe(address, mux)
+	},
+}
+
 func initializeLogger() {
 	if os.Getenv(config.Environment) == config.EnvironmentDevelopment {
 		zap.ReplaceGlobals(zap.Must(zap.NewDevelopment()))
@@ -48,6 +66,10 @@ func init() {
 	initializeLogger()
 
 	command.PersistentFlags().String(config.KeyConfig, "./deploy/config.yaml", "config file path")
+
+	stubVerifierCommand.Flags().String("address", "127.0.0.1:8081", "listen address")
+	stubVerifierCommand.Flags().String("token", "10000000-aaaa-bbbb-cccc-000000000001", "the only token accepted")
+	command.AddCommand(&stubVerifierCommand)
 }
 
 func main() {
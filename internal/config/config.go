@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"go.uber.org/zap"
 	"os"
+	"time"
 
 	"github.com/creasty/defaults"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/go-playground/validator/v10"
 	"gopkg.in/yaml.v3"
 )
@@ -18,11 +20,41 @@ const (
 )
 
 type File struct {
-	Environment string     `yaml:"environment" validate:"required" default:"development"`
-	Database    *Database  `yaml:"database"`
-	Redis       *Redis     `yaml:"redis"`
-	RSS3Chain   *RSS3Chain `yaml:"rss3_chain"`
-	AdminKey    string     `yaml:"admin_key"`
+	Environment  string        `yaml:"environment" validate:"required" default:"development"`
+	Database     *Database     `yaml:"database"`
+	Redis        *Redis        `yaml:"redis"`
+	RSS3Chain    *RSS3Chain    `yaml:"rss3_chain"`
+	AdminKey     string        `yaml:"admin_key"`
+	Note         *Note         `yaml:"note" default:"{}"`
+	Spam         *Spam         `yaml:"spam" default:"{}"`
+	Selection    *Selection    `yaml:"selection" default:"{}"`
+	Inbox        *Inbox        `yaml:"inbox" default:"{}"`
+	Search       *Search       `yaml:"search" default:"{}"`
+	Reaction     *Reaction     `yaml:"reaction" default:"{}"`
+	Expiry       *Expiry       `yaml:"expiry" default:"{}"`
+	Category     *Category     `yaml:"category" default:"{}"`
+	Moderation   *Moderation   `yaml:"moderation" default:"{}"`
+	Anonymity    *Anonymity    `yaml:"anonymity" default:"{}"`
+	Profile      *Profile      `yaml:"profile" default:"{}"`
+	Circle       *Circle       `yaml:"circle" default:"{}"`
+	Answer       *Answer       `yaml:"answer" default:"{}"`
+	Intercession *Intercession `yaml:"intercession" default:"{}"`
+	Capsule      *Capsule      `yaml:"capsule" default:"{}"`
+	Gift         *Gift         `yaml:"gift" default:"{}"`
+	Encryption   *Encryption   `yaml:"encryption" default:"{}"`
+	Chain        *Chain        `yaml:"chain" default:"{}"`
+	Challenge    *Challenge    `yaml:"challenge" default:"{}"`
+	Human        *Human        `yaml:"human" default:"{}"`
+	RateLimit    *RateLimit    `yaml:"rate_limit" default:"{}"`
+	Faucet       *Faucet       `yaml:"faucet" default:"{}"`
+	Mint         *Mint         `yaml:"mint" default:"{}"`
+	Proxy        *Proxy        `yaml:"proxy" default:"{}"`
+}
+
+// Proxy lists the reverse proxies whose X-Forwarded-For header is trusted for the client IP.
+type Proxy struct {
+	// TrustedProxies are CIDR ranges, the IP of the connection is used as is when it is empty.
+	TrustedProxies []string `yaml:"trusted_proxies" validate:"dive,cidr"`
 }
 
 type Database struct {
@@ -37,6 +69,243 @@ type RSS3Chain struct {
 	Endpoint string `yaml:"endpoint" validate:"required" default:" https://rpc.testnet.rss3.io"`
 }
 
+type Note struct {
+	MaxRunes          int    `yaml:"max_runes" validate:"gte=1" default:"500"`
+	MaxCombiningMarks int    `yaml:"max_combining_marks" validate:"gte=0" default:"3"`
+	Normalization     string `yaml:"normalization" validate:"oneof=nfc none" default:"nfc"`
+	Whitespace        string `yaml:"whitespace" validate:"oneof=collapse keep" default:"collapse"`
+	URLPolicy         string `yaml:"url_policy" validate:"oneof=allow strip reject" default:"allow"`
+}
+
+type Spam struct {
+	Threshold        int           `yaml:"threshold" validate:"gte=0,lte=64" default:"3"`
+	RecentPerAddress int64         `yaml:"recent_per_address" validate:"gte=1" default:"50"`
+	RecentGlobal     int64         `yaml:"recent_global" validate:"gte=1" default:"1000"`
+	Reward           string        `yaml:"reward" validate:"oneof=base none" default:"base"`
+	AddressTTL       time.Duration `yaml:"address_ttl" validate:"gt=0" default:"720h"`
+}
+
+type Selection struct {
+	Strategy   string        `yaml:"strategy" validate:"oneof=uniform weighted" default:"weighted"`
+	SampleSize int64         `yaml:"sample_size" validate:"gte=1" default:"32"`
+	HalfLife   time.Duration `yaml:"half_life" validate:"gt=0" default:"72h"`
+}
+
+type Inbox struct {
+	MaxItems  int           `yaml:"max_items" validate:"gte=1" default:"200"`
+	Retention time.Duration `yaml:"retention" validate:"gt=0" default:"720h"`
+}
+
+type Search struct {
+	// Language is the text search configuration notes are indexed with, queries always use the same one.
+	Language string `yaml:"language" validate:"required" default:"simple"`
+}
+
+type Reaction struct {
+	// MinTip is the smallest tip accepted with a reaction, in whole PRAY tokens.
+	MinTip int64 `yaml:"min_tip" validate:"gte=1" default:"1"`
+}
+
+type Expiry struct {
+	Default   time.Duration `yaml:"default" validate:"gt=0" default:"720h"`
+	Max       time.Duration `yaml:"max" validate:"gtefield=Default" default:"8760h"`
+	Interval  time.Duration `yaml:"interval" validate:"gt=0" default:"10m"`
+	BatchSize int64         `yaml:"batch_size" validate:"gte=1" default:"100"`
+}
+
+type Category struct {
+	Categories []string `yaml:"categories" validate:"dive,required" default:"[\"health\",\"family\",\"work\",\"gratitude\",\"study\",\"peace\"]"`
+	MaxTags    int      `yaml:"max_tags" validate:"gte=0" default:"5"`
+	// PeekPremium is charged on top of the peek price when a category is requested, in whole PRAY tokens.
+	PeekPremium int64 `yaml:"peek_premium" validate:"gte=0" default:"5"`
+}
+
+type Moderation struct {
+	// Moderators are the wallets allowed to call the admin endpoints.
+	Moderators []common.Address `yaml:"moderators"`
+}
+
+type Anonymity struct {
+	// Secret keys the pseudonyms of anonymous authors, the admin key is used when empty.
+	Secret string `yaml:"secret"`
+}
+
+type Profile struct {
+	MaxDisplayName int `yaml:"max_display_name" validate:"gte=1" default:"32"`
+	MaxBio         int `yaml:"max_bio" validate:"gte=0" default:"280"`
+	// Avatars are the preset avatar names a profile can pick instead of uploading an image.
+	Avatars []string `yaml:"avatars" validate:"dive,required" default:"[\"dove\",\"candle\",\"lotus\",\"olive\",\"sunrise\",\"star\"]"`
+	// AvatarDir is where uploaded avatars are stored and served from.
+	AvatarDir string `yaml:"avatar_dir" validate:"required" default:"data/avatars"`
+	// MaxAvatarSize is the largest accepted upload, in bytes.
+	MaxAvatarSize int64 `yaml:"max_avatar_size" validate:"gte=1" default:"262144"`
+}
+
+type Circle struct {
+	// MaxMembers caps joined members and pending invitations together.
+	MaxMembers   int64 `yaml:"max_members" validate:"gte=2" default:"50"`
+	MaxNameRunes int   `yaml:"max_name_runes" validate:"gte=1" default:"64"`
+	// DailyQuota is how many circle notes per address earn a reward each day, counted apart from public notes.
+	DailyQuota int64 `yaml:"daily_quota" validate:"gte=0" default:"5"`
+}
+
+type Answer struct {
+	// Bonus is minted once per answered note to every address that replied, in whole PRAY tokens, zero disables it.
+	Bonus int64 `yaml:"bonus" validate:"gte=0" default:"2"`
+	// MaxBonusRecipients caps how many repliers share in the bonus, the earliest repliers come first.
+	MaxBonusRecipients int `yaml:"max_bonus_recipients" validate:"gte=1" default:"20"`
+	// DailyQuota is how many answer bonuses an address may receive each day.
+	DailyQuota int64 `yaml:"daily_quota" validate:"gte=0" default:"10"`
+}
+
+type Intercession struct {
+	// Reward is minted to the interceding address, in whole PRAY tokens, zero disables it.
+	Reward int64 `yaml:"reward" validate:"gte=0" default:"1"`
+	// DailyQuota is how many intercessions per address earn the reward each day.
+	DailyQuota int64 `yaml:"daily_quota" validate:"gte=0" default:"10"`
+}
+
+type Capsule struct {
+	// MaxDelay is how far in the future a time capsule may be revealed.
+	MaxDelay  time.Duration `yaml:"max_delay" validate:"gt=0" default:"43800h"`
+	Interval  time.Duration `yaml:"interval" validate:"gt=0" default:"1m"`
+	BatchSize int64         `yaml:"batch_size" validate:"gte=1" default:"100"`
+}
+
+type Gift struct {
+	// MaxPerNote caps the gift a directed note may carry to its recipient, in whole PRAY tokens, zero disables gifts.
+	MaxPerNote int64 `yaml:"max_per_note" validate:"gte=0" default:"5"`
+	// DailyBudget caps the gifts an address may send each day, in whole PRAY tokens.
+	DailyBudget int64 `yaml:"daily_budget" validate:"gte=0" default:"20"`
+	// GlobalDailyBudget caps the gifts minted across all addresses each day, in whole PRAY tokens.
+	GlobalDailyBudget int64 `yaml:"global_daily_budget" validate:"gte=0" default:"1000"`
+}
+
+type Encryption struct {
+	// MaxEnvelope is the largest ECIES envelope accepted for an encrypted note, in bytes.
+	MaxEnvelope int `yaml:"max_envelope" validate:"gte=1" default:"4096"`
+}
+
+type Chain struct {
+	// MinLength and MaxLength bound how many contributors a starter may ask for, the starter included.
+	MinLength int `yaml:"min_length" validate:"gte=2" default:"3"`
+	MaxLength int `yaml:"max_length" validate:"gtefield=MinLength" default:"12"`
+	// HoldTimeout is how long a knocker holds a chain before it is passed to someone else.
+	HoldTimeout  time.Duration `yaml:"hold_timeout" validate:"gt=0" default:"1h"`
+	MaxLineRunes int           `yaml:"max_line_runes" validate:"gte=1" default:"200"`
+	// Reward is minted to every contributor once the chain completes, in whole PRAY tokens, zero disables it.
+	Reward int64 `yaml:"reward" validate:"gte=0" default:"3"`
+	// DailyQuota is how many chain rewards an address may receive each day.
+	DailyQuota int64 `yaml:"daily_quota" validate:"gte=0" default:"5"`
+}
+
+// Challenge is the proof-of-work policy for Knock, the difficulty is a number of leading zero bits.
+type Challenge struct {
+	Enabled bool `yaml:"enabled"`
+	// Secret keys the challenge signatures, the admin key is used when empty.
+	Secret string        `yaml:"secret"`
+	TTL    time.Duration `yaml:"ttl" validate:"gt=0" default:"2m"`
+	// BaseDifficulty applies when knocks are calm, MaxDifficulty caps what the adjustments below may add.
+	BaseDifficulty int `yaml:"base_difficulty" validate:"gte=0,lte=32" default:"16"`
+	MaxDifficulty  int `yaml:"max_difficulty" validate:"gtefield=BaseDifficulty,lte=32" default:"24"`
+	// KnocksPerBit adds one bit for every so many knocks in the last minute across all addresses.
+	KnocksPerBit int64 `yaml:"knocks_per_bit" validate:"gte=1" default:"120"`
+	// RiskPerBit adds one bit for every so many risk points of the requesting IP and address.
+	// A knock adds one point and a failed solution adds FailurePenalty, points are forgotten after RiskWindow.
+	RiskPerBit     int64         `yaml:"risk_per_bit" validate:"gte=1" default:"10"`
+	FailurePenalty int64         `yaml:"failure_penalty" validate:"gte=0" default:"5"`
+	RiskWindow     time.Duration `yaml:"risk_window" validate:"gt=0" default:"1h"`
+	// NewAddressRisk is the risk an address starts with until it has written a note.
+	NewAddressRisk int64 `yaml:"new_address_risk" validate:"gte=0" default:"20"`
+}
+
+// Human configures the CAPTCHA provider and which requests have to pass it.
+type Human struct {
+	// Provider is none, hcaptcha, turnstile or stub, none turns verification off whatever the rules say.
+	Provider string `yaml:"provider" validate:"oneof=none hcaptcha turnstile stub" default:"none"`
+	Secret   string `yaml:"secret"`
+	// Endpoint overrides the verify URL of the provider, the stub provider needs it.
+	Endpoint string        `yaml:"endpoint" validate:"required_if=Provider stub,omitempty,url"`
+	Timeout  time.Duration `yaml:"timeout" validate:"gt=0" default:"5s"`
+	// TrustTTL is how long signed requests of an address skip verification after it passed once.
+	TrustTTL time.Duration `yaml:"trust_ttl" validate:"gte=0" default:"24h"`
+	Faucet   HumanRule     `yaml:"faucet"`
+	Knock    HumanRule     `yaml:"knock"`
+}
+
+type HumanRule struct {
+	// Always requires verification on every request.
+	Always bool `yaml:"always"`
+	// MinGift requires verification for knocks carrying a gift of at least this many PRAY tokens, zero disables it.
+	MinGift int64 `yaml:"min_gift" validate:"gte=0"`
+}
+
+type RateLimit struct {
+	// Allowlist holds IPs, CIDR ranges and API keys that are never limited.
+	// Addresses are not accepted, a client can claim any address without signing.
+	Allowlist []string `yaml:"allowlist"`
+	// Routes maps "METHOD /path" as registered, for example "POST /pray/knock", to the rules a request must pass.
+	// The built-in routes apply when it is left out, an empty map turns limiting off.
+	Routes map[string][]RateLimitRule `yaml:"routes" validate:"dive,dive"`
+}
+
+// SetDefaults limits the routes that mint, burn or hand out funds when the config lists no routes.
+// Every route has an ip rule, since the address rules only count signed requests.
+func (r *RateLimit) SetDefaults() {
+	if r.Routes != nil {
+		return
+	}
+
+	r.Routes = map[string][]RateLimitRule{
+		"POST /pray/knock": {
+			{Key: "address", Algorithm: "sliding_window", Limit: 1, Window: 5 * time.Second},
+			{Key: "ip", Algorithm: "token_bucket", Limit: 30, Window: time.Minute},
+		},
+		"POST /pray/reply": {
+			{Key: "address", Algorithm: "sliding_window", Limit: 10, Window: time.Minute},
+			{Key: "ip", Algorithm: "token_bucket", Limit: 60, Window: time.Minute},
+		},
+		"POST /pray/peekNote": {
+			{Key: "address", Algorithm: "sliding_window", Limit: 10, Window: time.Minute},
+			{Key: "ip", Algorithm: "token_bucket", Limit: 30, Window: time.Minute},
+		},
+		"POST /pray/faucet": {
+			{Key: "ip", Algorithm: "sliding_window", Limit: 5, Window: 24 * time.Hour},
+		},
+	}
+}
+
+// RateLimitRule limits one dimension of a route, requests without a value for the dimension are not counted.
+type RateLimitRule struct {
+	// Key is ip, address or api_key.
+	Key string `yaml:"key" validate:"oneof=ip address api_key"`
+	// Algorithm is sliding_window, allowing Limit requests in any Window,
+	// or token_bucket, holding Limit tokens that refill evenly over Window.
+	Algorithm string        `yaml:"algorithm" validate:"oneof=sliding_window token_bucket"`
+	Limit     int64         `yaml:"limit" validate:"gte=1"`
+	Window    time.Duration `yaml:"window" validate:"gt=0"`
+}
+
+// Faucet guards the admin wallet against being drained, amounts are in whole RSS3.
+type Faucet struct {
+	// Cooldown is how long an address waits between two drips.
+	Cooldown time.Duration `yaml:"cooldown" validate:"gt=0" default:"24h"`
+	// DailyBudget caps what the faucet hands out across all addresses each day, days are in UTC.
+	DailyBudget float64 `yaml:"daily_budget" validate:"gt=0" default:"50"`
+	// MaxBalance turns away addresses that already hold more than this.
+	MaxBalance float64 `yaml:"max_balance" validate:"gte=0" default:"1"`
+	// Reserve is kept in the admin wallet for minting gas, a drip that would dip below it is refused.
+	Reserve float64 `yaml:"reserve" validate:"gte=0" default:"10"`
+}
+
+// Mint retries rewards whose mint failed.
+type Mint struct {
+	RetryInterval time.Duration `yaml:"retry_interval" validate:"gt=0" default:"5m"`
+	BatchSize     int           `yaml:"batch_size" validate:"gte=1" default:"20"`
+	// MaxAttempts is how often a mint is tried, the first attempt included, before it is left for an operator.
+	MaxAttempts int `yaml:"max_attempts" validate:"gte=1" default:"10"`
+}
+
 func Setup(configFilePath string) (*File, error) {
 	config, err := os.ReadFile(configFilePath)
 	if err != nil {
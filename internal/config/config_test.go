@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSetupOmittedSections(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config string
+		check  func(t *testing.T, file *File)
+	}{
+		{
+			name:   "no sections",
+			config: "environment: development\n",
+			check: func(t *testing.T, file *File) {
+				if file.Note == nil || file.Note.MaxRunes != 500 {
+					t.Errorf("note: got %+v, want the default section", file.Note)
+				}
+
+				if file.Mint == nil || file.Mint.RetryInterval != 5*time.Minute {
+					t.Errorf("mint: got %+v, want the default section", file.Mint)
+				}
+
+				if file.RateLimit == nil || len(file.RateLimit.Routes["POST /pray/knock"]) == 0 {
+					t.Errorf("rate_limit: got %+v, want the built-in routes", file.RateLimit)
+				}
+			},
+		},
+		{
+			name:   "partial section",
+			config: "environment: development\nnote:\n  max_runes: 100\n",
+			check: func(t *testing.T, file *File) {
+				if file.Note.MaxRunes != 100 {
+					t.Errorf("note.max_runes: got %d, want 100", file.Note.MaxRunes)
+				}
+
+				if file.Note.Normalization != "nfc" {
+					t.Errorf("note.normalization: got %q, want the default nfc", file.Note.Normalization)
+				}
+			},
+		},
+		{
+			name:   "rate limiting turned off",
+			config: "environment: development\nrate_limit:\n  routes: {}\n",
+			check: func(t *testing.T, file *File) {
+				if len(file.RateLimit.Routes) != 0 {
+					t.Errorf("rate_limit.routes: got %v, want none", file.RateLimit.Routes)
+				}
+			},
+		},
+		{
+			name:   "configured routes replace the built-in ones",
+			config: "environment: development\nrate_limit:\n  routes:\n    POST /pray/reply:\n      - key: ip\n        algorithm: sliding_window\n        limit: 1\n        window: 1s\n",
+			check: func(t *testing.T, file *File) {
+				if _, ok := file.RateLimit.Routes["POST /pray/knock"]; ok || len(file.RateLimit.Routes) != 1 {
+					t.Errorf("rate_limit.routes: got %v, want the reply route only", file.RateLimit.Routes)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.config), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			file, err := Setup(path)
+			if err != nil {
+				t.Fatalf("setup: %v", err)
+			}
+
+			tt.check(t, file)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/internal/database/table"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AnswerNote marks a note as answered and stores the testimony, it reports whether the note was answered for the first time.
+func (c *Client) AnswerNote(ctx context.Context, messageID, testimony string) (bool, error) {
+	var first bool
+
+	err := c.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var note table.Note
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&note, "message_id = ? AND deleted_at IS NULL", messageID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrorRowNotFound
+			}
+
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"testimony": testimony,
+		}
+
+		if note.AnsweredAt == nil {
+			first = true
+			updates["answered_at"] = time.Now()
+		}
+
+		return tx.Model(&note).Updates(updates).Error
+	})
+
+	return first, err
+}
+
+// ClaimAnswerBonus reports whether the answer bonus of a note is still unpaid and marks it paid in the same step.
+func (c *Client) ClaimAnswerBonus(ctx context.Context, messageID string) (bool, error) {
+	result := c.database.WithContext(ctx).
+		Model((*table.Note)(nil)).
+		Where("message_id = ? AND answered_at IS NOT NULL AND answer_bonus_at IS NULL", messageID).
+		Update("answer_bonus_at", time.Now())
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindAnsweredNotes returns public answered notes, most recently answered first.
+func (c *Client) FindAnsweredNotes(ctx context.Context, query schema.AnsweredNoteQuery) ([]*schema.Note, error) {
+	var notes []table.Note
+
+	err := c.database.WithContext(ctx).
+		Where(`answered_at IS NOT NULL AND NOT hidden AND deleted_at IS NULL AND circle_id = ''`).
+		Order("answered_at DESC, id DESC").
+		Offset(query.Offset).
+		Limit(query.Limit).
+		Find(&notes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*schema.Note, 0, len(notes))
+
+	for _, note := range notes {
+		data, err := note.Export()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, data)
+	}
+
+	if err := c.attachTags(ctx, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindRepliers returns every address that replied to the note, in order of their first reply.
+func (c *Client) FindRepliers(ctx context.Context, noteID string) ([]common.Address, error) {
+	var addresses []common.Address
+
+	err := c.database.WithContext(ctx).
+		Model((*table.Reply)(nil)).
+		Select("address").
+		Where("note_id = ?", noteID).
+		Group("address").
+		Order("MIN(id)").
+		Pluck("address", &addresses).Error
+
+	return addresses, err
+}
+
+// FindVerifiedRepliers returns the addresses with a verified reply to the note, in order of their first verified reply.
+func (c *Client) FindVerifiedRepliers(ctx context.Context, noteID string) ([]common.Address, error) {
+	var addresses []common.Address
+
+	err := c.database.WithContext(ctx).
+		Model((*table.Reply)(nil)).
+		Select("address").
+		Where("note_id = ? AND verified", noteID).
+		Group("address").
+		Order("MIN(id)").
+		Pluck("address", &addresses).Error
+
+	return addresses, err
+}
+
+// FindReactors returns every address that reacted to the note.
+func (c *Client) FindReactors(ctx context.Context, noteID string) ([]common.Address, error) {
+	var addresses []common.Address
+
+	err := c.database.WithContext(ctx).
+		Model((*table.Reaction)(nil)).
+		Distinct("address").
+		Where("note_id = ?", noteID).
+		Pluck("address", &addresses).Error
+
+	return addresses, err
+}
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+
+	"github.com/brucexc/pray-to-earn/internal/database/table"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm/clause"
+)
+
+// SaveBurn records a burn transaction as spent and reports whether it is new, a transaction already spent is left as it was.
+func (c *Client) SaveBurn(ctx context.Context, data *schema.Burn) (bool, error) {
+	var burn table.Burn
+
+	if err := burn.Import(data); err != nil {
+		return false, err
+	}
+
+	result := c.database.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&burn)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	data.ID = burn.ID
+
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteBurn releases a burn transaction whose request did not go through, so it can be spent again.
+func (c *Client) DeleteBurn(ctx context.Context, transactionHash common.Hash) error {
+	return c.database.WithContext(ctx).
+		Where("transaction_hash = ?", transactionHash).
+		Delete((*table.Burn)(nil)).Error
+}
@@ -0,0 +1,241 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/internal/database/table"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SaveChain stores a chain prayer note with the starter's line as its first link, it waits open for the next contributor.
+// The note stays hidden until the chain completes.
+func (c *Client) SaveChain(ctx context.Context, data *schema.Note, length int) (*schema.Chain, error) {
+	var note table.Note
+
+	data.Kind = schema.NoteKindChain
+	data.Hidden = true
+
+	if err := note.Import(data); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	note.ChainLength = length
+	note.ChainStatus = schema.ChainStatusOpen
+	note.ChainWaitingAt = &now
+
+	var result *schema.Chain
+
+	err := c.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&note).Error; err != nil {
+			return err
+		}
+
+		data.ID = note.ID
+
+		link := table.ChainLink{
+			ChainID:  note.MessageID,
+			Position: 0,
+			Address:  note.Address,
+			Line:     note.Note,
+		}
+
+		if err := tx.Create(&link).Error; err != nil {
+			return err
+		}
+
+		chain, err := note.ExportChain()
+		if err != nil {
+			return err
+		}
+
+		result = chain
+
+		return loadChainLinks(tx, chain)
+	})
+
+	return result, err
+}
+
+func (c *Client) FindChain(ctx context.Context, chainID string) (*schema.Chain, error) {
+	var note table.Note
+
+	if err := c.database.WithContext(ctx).First(&note, "message_id = ? AND kind = ? AND deleted_at IS NULL", chainID, schema.NoteKindChain).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrorRowNotFound
+		}
+
+		return nil, err
+	}
+
+	chain, err := note.ExportChain()
+	if err != nil {
+		return nil, err
+	}
+
+	return chain, loadChainLinks(c.database.WithContext(ctx), chain)
+}
+
+// AssignChain hands the address the chain it should contribute to next, assignments made before the cutoff have lapsed.
+// An address keeps getting the chain it already holds, otherwise it is given the open chain that waited longest
+// among those it has not contributed to yet. It returns ErrorRowNotFound when no chain is waiting.
+func (c *Client) AssignChain(ctx context.Context, address common.Address, cutoff time.Time) (*schema.Chain, error) {
+	var result *schema.Chain
+
+	err := c.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var note table.Note
+
+		err := tx.Where("kind = ? AND deleted_at IS NULL", schema.NoteKindChain).
+			Where("chain_status = ? AND chain_holder = ? AND chain_assigned_at > ?", schema.ChainStatusAssigned, address, cutoff).
+			Take(&note).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if err != nil {
+			err = tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where("kind = ? AND deleted_at IS NULL", schema.NoteKindChain).
+				Where("chain_status = ? OR (chain_status = ? AND chain_assigned_at <= ?)", schema.ChainStatusOpen, schema.ChainStatusAssigned, cutoff).
+				Where(`NOT EXISTS (SELECT 1 FROM "chain_link" WHERE "chain_link"."chain_id" = "note"."message_id" AND "chain_link"."address" = ?)`, address).
+				Order("chain_waiting_at ASC").
+				Take(&note).Error
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrorRowNotFound
+				}
+
+				return err
+			}
+
+			// chain_waiting_at is left alone, so a lapsed chain keeps its place at the front of the queue
+			now := time.Now()
+
+			if err := tx.Model(&note).UpdateColumns(map[string]interface{}{
+				"chain_status":      schema.ChainStatusAssigned,
+				"chain_holder":      address,
+				"chain_assigned_at": now,
+			}).Error; err != nil {
+				return err
+			}
+
+			note.ChainStatus = schema.ChainStatusAssigned
+			note.ChainHolder = &address
+			note.ChainAssignedAt = &now
+		}
+
+		chain, err := note.ExportChain()
+		if err != nil {
+			return err
+		}
+
+		result = chain
+
+		return loadChainLinks(tx, chain)
+	})
+
+	return result, err
+}
+
+// AppendChain adds the holder's line to the chain and passes it on. Once the chain reaches its length it is completed:
+// the lines become the text of the note, which is no longer hidden and expires after expiresIn unless that is zero.
+// It returns ErrorChainNotHeld unless the address holds an assignment made after the cutoff.
+func (c *Client) AppendChain(ctx context.Context, chainID string, address common.Address, line string, cutoff time.Time, expiresIn time.Duration) (*schema.Chain, error) {
+	var result *schema.Chain
+
+	err := c.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var note table.Note
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&note, "message_id = ? AND kind = ? AND deleted_at IS NULL", chainID, schema.NoteKindChain).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrorRowNotFound
+			}
+
+			return err
+		}
+
+		if note.ChainStatus != schema.ChainStatusAssigned || note.ChainHolder == nil || *note.ChainHolder != address || !note.ChainAssignedAt.After(cutoff) {
+			return ErrorChainNotHeld
+		}
+
+		var lines []string
+
+		if err := tx.Model((*table.ChainLink)(nil)).Where("chain_id = ?", chainID).Order("position ASC").Pluck("line", &lines).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(&table.ChainLink{
+			ChainID:  chainID,
+			Position: len(lines),
+			Address:  address,
+			Line:     line,
+		}).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		updates := map[string]interface{}{
+			"chain_status":      schema.ChainStatusOpen,
+			"chain_holder":      nil,
+			"chain_assigned_at": nil,
+			"chain_waiting_at":  now,
+		}
+
+		if len(lines)+1 >= note.ChainLength {
+			updates["chain_status"] = schema.ChainStatusCompleted
+			updates["chain_completed_at"] = now
+			updates["note"] = strings.Join(append(lines, line), "\n")
+			updates["hidden"] = false
+
+			if expiresIn > 0 {
+				updates["expires_at"] = now.Add(expiresIn)
+			}
+		}
+
+		if err := tx.Model(&note).UpdateColumns(updates).Error; err != nil {
+			return err
+		}
+
+		if err := tx.First(&note, "id = ?", note.ID).Error; err != nil {
+			return err
+		}
+
+		chain, err := note.ExportChain()
+		if err != nil {
+			return err
+		}
+
+		result = chain
+
+		return loadChainLinks(tx, chain)
+	})
+
+	return result, err
+}
+
+func loadChainLinks(tx *gorm.DB, chain *schema.Chain) error {
+	var links []table.ChainLink
+
+	if err := tx.Where("chain_id = ?", chain.ID).Order("position ASC").Find(&links).Error; err != nil {
+		return err
+	}
+
+	chain.Links = make([]*schema.ChainLink, 0, len(links))
+
+	for _, link := range links {
+		data, err := link.Export()
+		if err != nil {
+			return err
+		}
+
+		chain.Links = append(chain.Links, data)
+	}
+
+	return nil
+}
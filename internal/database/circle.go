@@ -0,0 +1,160 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/internal/database/table"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SaveCircle creates a circle with its owner as the first joined member.
+func (c *Client) SaveCircle(ctx context.Context, data *schema.Circle) error {
+	var circle table.Circle
+
+	if err := circle.Import(data); err != nil {
+		return err
+	}
+
+	return c.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&circle).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		if err := tx.Create(&table.CircleMember{
+			CircleID:  circle.CircleID,
+			Address:   circle.Owner,
+			Status:    schema.CircleMemberStatusJoined,
+			InvitedBy: circle.Owner,
+			JoinedAt:  &now,
+		}).Error; err != nil {
+			return err
+		}
+
+		data.CreatedAt = circle.CreatedAt.Unix()
+
+		return nil
+	})
+}
+
+func (c *Client) FindCircle(ctx context.Context, circleID string) (*schema.Circle, error) {
+	var circle table.Circle
+
+	if err := c.database.WithContext(ctx).First(&circle, "circle_id = ?", circleID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrorRowNotFound
+		}
+
+		return nil, err
+	}
+
+	return circle.Export()
+}
+
+// FindCircles returns the circles the address has joined or been invited to.
+func (c *Client) FindCircles(ctx context.Context, address common.Address) ([]*schema.CircleMembership, error) {
+	var rows []struct {
+		table.Circle
+		Status string `gorm:"column:status"`
+	}
+
+	err := c.database.WithContext(ctx).
+		Table("circle").
+		Select(`"circle".*, "circle_member"."status"`).
+		Joins(`JOIN "circle_member" ON "circle_member"."circle_id" = "circle"."circle_id"`).
+		Where(`"circle_member"."address" = ?`, address).
+		Order(`"circle"."id" DESC`).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*schema.CircleMembership, 0, len(rows))
+
+	for _, row := range rows {
+		circle, err := row.Circle.Export()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, &schema.CircleMembership{
+			Circle: circle,
+			Status: row.Status,
+		})
+	}
+
+	return result, nil
+}
+
+func (c *Client) FindCircleMember(ctx context.Context, circleID string, address common.Address) (*schema.CircleMember, error) {
+	var member table.CircleMember
+
+	if err := c.database.WithContext(ctx).First(&member, "circle_id = ? AND address = ?", circleID, address).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrorRowNotFound
+		}
+
+		return nil, err
+	}
+
+	return member.Export()
+}
+
+// CountCircleMembers counts joined members and pending invitations alike.
+func (c *Client) CountCircleMembers(ctx context.Context, circleID string) (int64, error) {
+	var count int64
+
+	err := c.database.WithContext(ctx).
+		Model((*table.CircleMember)(nil)).
+		Where("circle_id = ?", circleID).
+		Count(&count).Error
+
+	return count, err
+}
+
+// InviteCircleMembers invites the addresses and returns how many invitations are new, existing members are left alone.
+func (c *Client) InviteCircleMembers(ctx context.Context, circleID string, invitedBy common.Address, addresses []common.Address) (int64, error) {
+	if len(addresses) == 0 {
+		return 0, nil
+	}
+
+	members := make([]table.CircleMember, 0, len(addresses))
+	for _, address := range addresses {
+		members = append(members, table.CircleMember{
+			CircleID:  circleID,
+			Address:   address,
+			Status:    schema.CircleMemberStatusInvited,
+			InvitedBy: invitedBy,
+		})
+	}
+
+	result := c.database.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&members)
+
+	return result.RowsAffected, result.Error
+}
+
+// JoinCircle accepts a pending invitation, it returns ErrorRowNotFound when there is none.
+func (c *Client) JoinCircle(ctx context.Context, circleID string, address common.Address) error {
+	result := c.database.WithContext(ctx).
+		Model((*table.CircleMember)(nil)).
+		Where("circle_id = ? AND address = ? AND status = ?", circleID, address, schema.CircleMemberStatusInvited).
+		Updates(map[string]interface{}{
+			"status":    schema.CircleMemberStatusJoined,
+			"joined_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrorRowNotFound
+	}
+
+	return nil
+}
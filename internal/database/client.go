@@ -7,20 +7,27 @@ import (
 	"fmt"
 	"github.com/brucexc/pray-to-earn/internal/database/table"
 	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/pressly/goose/v3"
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"moul.io/zapgorm2"
+	"time"
 )
 
 //go:embed migration/*.sql
 var migrationFS embed.FS
 
 var (
-	ErrorRowNotFound = errors.New("row not found")
+	ErrorRowNotFound  = errors.New("row not found")
+	ErrorChainNotHeld = errors.New("chain not held")
 )
 
+// revealed excludes time capsules that are still sealed and chain prayers that are not completed yet.
+const revealed = `("note"."reveal_at" IS NULL OR "note"."reveal_at" <= now()) AND ("note"."kind" <> 'chain' OR "note"."chain_status" = 'completed')`
+
 type Client struct {
 	database *gorm.DB
 }
@@ -62,7 +69,200 @@ func (c *Client) SaveNote(ctx context.Context, data *schema.Note) error {
 		return err
 	}
 
-	return c.database.WithContext(ctx).Create(&note).Error
+	return c.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&note).Error; err != nil {
+			return err
+		}
+
+		data.ID = note.ID
+
+		if len(data.Tags) == 0 {
+			return nil
+		}
+
+		tags := make([]table.NoteTag, 0, len(data.Tags))
+		for _, tag := range data.Tags {
+			tags = append(tags, table.NoteTag{NoteID: data.MessageID, Tag: tag})
+		}
+
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error
+	})
+}
+
+// FindNote finds a revealed note, archived notes are only found when viewer is their author.
+func (c *Client) FindNote(ctx context.Context, messageID string, viewer *common.Address) (*schema.Note, error) {
+	var note table.Note
+
+	databaseStatement := c.database.WithContext(ctx).Where(revealed)
+
+	if viewer != nil {
+		databaseStatement = databaseStatement.Where("archived_at IS NULL OR address = ?", *viewer)
+	} else {
+		databaseStatement = databaseStatement.Where("archived_at IS NULL")
+	}
+
+	if err := databaseStatement.First(&note, "message_id = ? AND deleted_at IS NULL", messageID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrorRowNotFound
+		}
+
+		return nil, err
+	}
+
+	data, err := note.Export()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.attachTags(ctx, []*schema.Note{data}); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// FindNoteForModeration finds a note whatever its state, including deleted, archived and sealed notes.
+// It is meant for moderators only and skips the tags.
+func (c *Client) FindNoteForModeration(ctx context.Context, messageID string) (*schema.Note, error) {
+	var note table.Note
+
+	if err := c.database.WithContext(ctx).First(&note, "message_id = ?", messageID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrorRowNotFound
+		}
+
+		return nil, err
+	}
+
+	return note.Export()
+}
+
+// FindCapsule finds a note whether or not it is still sealed, it is meant for publishing capsules only.
+func (c *Client) FindCapsule(ctx context.Context, messageID string) (*schema.Note, error) {
+	var note table.Note
+
+	if err := c.database.WithContext(ctx).First(&note, "message_id = ? AND deleted_at IS NULL", messageID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrorRowNotFound
+		}
+
+		return nil, err
+	}
+
+	data, err := note.Export()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.attachTags(ctx, []*schema.Note{data}); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (c *Client) FindNotes(ctx context.Context, query schema.NoteQuery) ([]*schema.Note, error) {
+	databaseStatement := c.database.WithContext(ctx).Where("deleted_at IS NULL")
+
+	if query.Address != nil {
+		databaseStatement = databaseStatement.Where("address = ?", *query.Address)
+	}
+
+	if query.Archived {
+		databaseStatement = databaseStatement.Where("archived_at IS NOT NULL")
+	} else {
+		databaseStatement = databaseStatement.Where("archived_at IS NULL")
+	}
+
+	if query.Sealed {
+		databaseStatement = databaseStatement.Where("reveal_at > now()")
+	} else {
+		databaseStatement = databaseStatement.Where(revealed)
+	}
+
+	if query.Cursor != nil {
+		databaseStatement = databaseStatement.Where("id < ?", *query.Cursor)
+	}
+
+	var notes []table.Note
+
+	if err := databaseStatement.Order("id DESC").Limit(query.Limit).Find(&notes).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*schema.Note, 0, len(notes))
+
+	for _, note := range notes {
+		data, err := note.Export()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, data)
+	}
+
+	if err := c.attachTags(ctx, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *Client) SaveReply(ctx context.Context, data *schema.Reply) error {
+	var reply table.Reply
+
+	if err := reply.Import(data); err != nil {
+		return err
+	}
+
+	if err := c.database.WithContext(ctx).Create(&reply).Error; err != nil {
+		return err
+	}
+
+	data.ID = reply.ID
+
+	return nil
+}
+
+func (c *Client) FindReply(ctx context.Context, noteID string, id uint64) (*schema.Reply, error) {
+	var reply table.Reply
+
+	if err := c.database.WithContext(ctx).First(&reply, "note_id = ? AND id = ?", noteID, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrorRowNotFound
+		}
+
+		return nil, err
+	}
+
+	return reply.Export()
+}
+
+func (c *Client) FindReplies(ctx context.Context, query schema.ReplyQuery) ([]*schema.Reply, error) {
+	databaseStatement := c.database.WithContext(ctx).Where("note_id = ?", query.NoteID)
+
+	if query.Cursor != nil {
+		databaseStatement = databaseStatement.Where("id > ?", *query.Cursor)
+	}
+
+	var replies []table.Reply
+
+	if err := databaseStatement.Order("id ASC").Limit(query.Limit).Find(&replies).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*schema.Reply, 0, len(replies))
+
+	for _, reply := range replies {
+		data, err := reply.Export()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, data)
+	}
+
+	return result, nil
 }
 
 func Dial(_ context.Context, dataSourceName string) (*Client, error) {
@@ -82,3 +282,147 @@ func Dial(_ context.Context, dataSourceName string) (*Client, error) {
 		database: databaseClient,
 	}, nil
 }
+
+// ArchiveNotes marks the notes as moved out of the active pool.
+func (c *Client) ArchiveNotes(ctx context.Context, messageIDs []string) error {
+	return c.database.WithContext(ctx).
+		Model((*table.Note)(nil)).
+		Where("message_id IN ? AND archived_at IS NULL", messageIDs).
+		Update("archived_at", time.Now()).Error
+}
+
+// ReviseNote replaces the text of a note and keeps the previous text as a revision.
+func (c *Client) ReviseNote(ctx context.Context, messageID, text, language string) error {
+	return c.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var note table.Note
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&note, "message_id = ? AND deleted_at IS NULL", messageID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrorRowNotFound
+			}
+
+			return err
+		}
+
+		if err := tx.Create(&table.NoteRevision{NoteID: messageID, Note: note.Note}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&note).Updates(map[string]interface{}{
+			"note":      text,
+			"language":  language,
+			"edited_at": time.Now(),
+		}).Error
+	})
+}
+
+// DeleteNote withdraws a note, it is kept for moderation but no longer returned by any query.
+func (c *Client) DeleteNote(ctx context.Context, messageID string) error {
+	result := c.database.WithContext(ctx).
+		Model((*table.Note)(nil)).
+		Where("message_id = ? AND deleted_at IS NULL", messageID).
+		Update("deleted_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrorRowNotFound
+	}
+
+	return nil
+}
+
+func (c *Client) FindNoteRevisions(ctx context.Context, messageID string) ([]*schema.NoteRevision, error) {
+	var revisions []table.NoteRevision
+
+	if err := c.database.WithContext(ctx).Where("note_id = ?", messageID).Order("id ASC").Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*schema.NoteRevision, 0, len(revisions))
+
+	for _, revision := range revisions {
+		data, err := revision.Export()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, data)
+	}
+
+	return result, nil
+}
+
+// SearchNotes ranks visible public notes against a web search style query using the given text search configuration.
+func (c *Client) SearchNotes(ctx context.Context, query schema.NoteSearchQuery) ([]*schema.NoteSearchResult, error) {
+	var rows []struct {
+		table.Note
+		Rank float64 `gorm:"column:rank"`
+	}
+
+	err := c.database.WithContext(ctx).
+		Table("note, websearch_to_tsquery(?::regconfig, ?) AS query", query.Language, query.Query).
+		Select(`"note".*, ts_rank("note"."search", query) AS rank`).
+		Where(`"note"."search" @@ query AND NOT "note"."hidden" AND "note"."archived_at" IS NULL AND "note"."deleted_at" IS NULL AND "note"."circle_id" = ''`).
+		Where(revealed).
+		Order(`rank DESC, "note"."id" DESC`).
+		Offset(query.Offset).
+		Limit(query.Limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*schema.NoteSearchResult, 0, len(rows))
+
+	for _, row := range rows {
+		note, err := row.Note.Export()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, &schema.NoteSearchResult{
+			Note: note,
+			Rank: row.Rank,
+		})
+	}
+
+	notes := make([]*schema.Note, 0, len(result))
+	for _, item := range result {
+		notes = append(notes, item.Note)
+	}
+
+	if err := c.attachTags(ctx, notes); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// attachTags loads the tags of the notes in a single query.
+func (c *Client) attachTags(ctx context.Context, notes []*schema.Note) error {
+	if len(notes) == 0 {
+		return nil
+	}
+
+	index := make(map[string]*schema.Note, len(notes))
+	messageIDs := make([]string, 0, len(notes))
+
+	for _, note := range notes {
+		index[note.MessageID] = note
+		messageIDs = append(messageIDs, note.MessageID)
+	}
+
+	var tags []table.NoteTag
+
+	if err := c.database.WithContext(ctx).Where("note_id IN ?", messageIDs).Order("tag").Find(&tags).Error; err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		index[tag.NoteID].Tags = append(index[tag.NoteID].Tags, tag.Tag)
+	}
+
+	return nil
+}
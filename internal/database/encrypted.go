@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/internal/database/table"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (c *Client) FindEncryptionKey(ctx context.Context, address common.Address) (*schema.EncryptionKey, error) {
+	var key table.EncryptionKey
+
+	if err := c.database.WithContext(ctx).First(&key, "address = ?", address).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrorRowNotFound
+		}
+
+		return nil, err
+	}
+
+	return key.Export()
+}
+
+// SaveEncryptionKey registers or rotates the public key of an address.
+func (c *Client) SaveEncryptionKey(ctx context.Context, data *schema.EncryptionKey) error {
+	var key table.EncryptionKey
+
+	if err := key.Import(data); err != nil {
+		return err
+	}
+
+	key.UpdatedAt = time.Now()
+
+	err := c.database.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "address"}},
+		DoUpdates: clause.AssignmentColumns([]string{"public_key", "updated_at"}),
+	}).Create(&key).Error
+	if err != nil {
+		return err
+	}
+
+	data.UpdatedAt = key.UpdatedAt.Unix()
+
+	return nil
+}
+
+func (c *Client) SaveEncryptedNote(ctx context.Context, data *schema.EncryptedNote) error {
+	var note table.EncryptedNote
+
+	if err := note.Import(data); err != nil {
+		return err
+	}
+
+	if err := c.database.WithContext(ctx).Create(&note).Error; err != nil {
+		return err
+	}
+
+	data.ID = note.ID
+	data.CreatedAt = note.CreatedAt.Unix()
+
+	return nil
+}
+
+func (c *Client) FindEncryptedNote(ctx context.Context, messageID string) (*schema.EncryptedNote, error) {
+	var note table.EncryptedNote
+
+	if err := c.database.WithContext(ctx).First(&note, "message_id = ?", messageID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrorRowNotFound
+		}
+
+		return nil, err
+	}
+
+	return note.Export()
+}
+
+func (c *Client) FindEncryptedNotes(ctx context.Context, query schema.EncryptedNoteQuery) ([]*schema.EncryptedNote, error) {
+	databaseStatement := c.database.WithContext(ctx).Where("recipient = ?", query.Recipient)
+
+	if query.Cursor != nil {
+		databaseStatement = databaseStatement.Where("id < ?", *query.Cursor)
+	}
+
+	var notes []table.EncryptedNote
+
+	if err := databaseStatement.Order("id DESC").Limit(query.Limit).Find(&notes).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*schema.EncryptedNote, 0, len(notes))
+
+	for _, note := range notes {
+		data, err := note.Export()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, data)
+	}
+
+	return result, nil
+}
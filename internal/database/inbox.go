@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/internal/database/table"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func (c *Client) SaveInboxItem(ctx context.Context, data *schema.InboxItem) error {
+	var item table.InboxItem
+
+	if err := item.Import(data); err != nil {
+		return err
+	}
+
+	if err := c.database.WithContext(ctx).Create(&item).Error; err != nil {
+		return err
+	}
+
+	data.ID = item.ID
+
+	return nil
+}
+
+// PruneInbox keeps at most maxItems of the newest items of the address and drops anything created before the cutoff.
+func (c *Client) PruneInbox(ctx context.Context, address common.Address, maxItems int, before time.Time) error {
+	newest := c.database.Model((*table.InboxItem)(nil)).
+		Select("id").
+		Where("address = ?", address).
+		Order("id DESC").
+		Limit(maxItems)
+
+	return c.database.WithContext(ctx).
+		Where("address = ?", address).
+		Where("id NOT IN (?) OR created_at < ?", newest, before).
+		Delete((*table.InboxItem)(nil)).Error
+}
+
+func (c *Client) FindInboxItems(ctx context.Context, query schema.InboxQuery) ([]*schema.InboxItem, error) {
+	databaseStatement := c.database.WithContext(ctx).Where("address = ?", query.Address)
+
+	if query.UnreadOnly {
+		databaseStatement = databaseStatement.Where("NOT read")
+	}
+
+	if query.Cursor != nil {
+		databaseStatement = databaseStatement.Where("id < ?", *query.Cursor)
+	}
+
+	var items []table.InboxItem
+
+	if err := databaseStatement.Order("id DESC").Limit(query.Limit).Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*schema.InboxItem, 0, len(items))
+
+	for _, item := range items {
+		data, err := item.Export()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, data)
+	}
+
+	return result, nil
+}
+
+func (c *Client) CountUnreadInboxItems(ctx context.Context, address common.Address) (int64, error) {
+	var count int64
+
+	if err := c.database.WithContext(ctx).Model((*table.InboxItem)(nil)).Where("address = ? AND NOT read", address).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// MarkInboxItemsRead marks the given items of the address as read, or all of them when ids is empty.
+func (c *Client) MarkInboxItemsRead(ctx context.Context, address common.Address, ids []uint64) (int64, error) {
+	databaseStatement := c.database.WithContext(ctx).Model((*table.InboxItem)(nil)).Where("address = ? AND NOT read", address)
+
+	if len(ids) > 0 {
+		databaseStatement = databaseStatement.Where("id IN ?", ids)
+	}
+
+	result := databaseStatement.Update("read", true)
+
+	return result.RowsAffected, result.Error
+}
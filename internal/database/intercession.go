@@ -0,0 +1,41 @@
+package database
+
+import (
+	"context"
+
+	"github.com/brucexc/pray-to-earn/internal/database/table"
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SaveIntercession records that the address prayed for the note and returns the new counter of the note,
+// it reports false without touching the counter when the address already interceded.
+func (c *Client) SaveIntercession(ctx context.Context, noteID string, address common.Address) (bool, int64, error) {
+	var (
+		created bool
+		count   int64
+	)
+
+	err := c.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&table.Intercession{
+			NoteID:  noteID,
+			Address: address,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		created = result.RowsAffected > 0
+
+		if created {
+			if err := tx.Model((*table.Note)(nil)).Where("message_id = ?", noteID).Update("intercessions", gorm.Expr("intercessions + 1")).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model((*table.Note)(nil)).Where("message_id = ?", noteID).Pluck("intercessions", &count).Error
+	})
+
+	return created, count, err
+}
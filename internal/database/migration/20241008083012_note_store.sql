@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "note"
+    ADD COLUMN "message_id" TEXT,
+    ADD COLUMN "note"       TEXT NOT NULL DEFAULT '',
+    ALTER COLUMN "type" SET DEFAULT '';
+
+-- notes recorded before the note store have no message, their row id keeps them unique
+UPDATE "note"
+SET "message_id" = "id"::TEXT
+WHERE "message_id" IS NULL;
+
+ALTER TABLE "note"
+    ALTER COLUMN "message_id" SET NOT NULL,
+    ALTER COLUMN "note" DROP DEFAULT;
+
+CREATE UNIQUE INDEX "idx_note_message_id" ON "note" ("message_id");
+CREATE INDEX "idx_note_address" ON "note" ("address", "id" DESC);
+
+CREATE TABLE "reply"
+(
+    "id"                 bigint      GENERATED BY DEFAULT AS IDENTITY (INCREMENT 1 MINVALUE 0 START 0),
+    "note_id"            TEXT        NOT NULL,
+    "address"            bytea       NOT NULL,
+    "reply"              TEXT        NOT NULL,
+    "created_at"         timestamptz NOT NULL DEFAULT now(),
+
+    CONSTRAINT "reply_pkey" PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_reply_note_id" ON "reply" ("note_id", "id");
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "reply";
+
+ALTER TABLE "note"
+    DROP COLUMN "message_id",
+    DROP COLUMN "note",
+    ALTER COLUMN "type" DROP DEFAULT;
+-- +goose StatementEnd
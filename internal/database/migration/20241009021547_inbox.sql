@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "inbox"
+(
+    "id"                 bigint      GENERATED BY DEFAULT AS IDENTITY (INCREMENT 1 MINVALUE 0 START 0),
+    "address"            bytea       NOT NULL,
+    "type"               TEXT        NOT NULL,
+    "note_id"            TEXT        NOT NULL,
+    "reply_id"           bigint,
+    "actor"              bytea       NOT NULL,
+    "read"               boolean     NOT NULL DEFAULT false,
+    "created_at"         timestamptz NOT NULL DEFAULT now(),
+
+    CONSTRAINT "inbox_pkey" PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_inbox_address" ON "inbox" ("address", "id" DESC);
+CREATE INDEX "idx_inbox_unread" ON "inbox" ("address") WHERE NOT "read";
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "inbox";
+-- +goose StatementEnd
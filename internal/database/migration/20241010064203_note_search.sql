@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "note"
+    ADD COLUMN "hidden"          boolean   NOT NULL DEFAULT false,
+    ADD COLUMN "search_language" regconfig NOT NULL DEFAULT 'simple',
+    ADD COLUMN "search"          tsvector  GENERATED ALWAYS AS (to_tsvector("search_language", "note")) STORED;
+
+CREATE INDEX "idx_note_search" ON "note" USING GIN ("search");
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX "idx_note_search";
+
+ALTER TABLE "note"
+    DROP COLUMN "search",
+    DROP COLUMN "search_language",
+    DROP COLUMN "hidden";
+-- +goose StatementEnd
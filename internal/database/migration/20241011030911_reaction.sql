@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "reaction"
+(
+    "id"                 bigint      GENERATED BY DEFAULT AS IDENTITY (INCREMENT 1 MINVALUE 0 START 0),
+    "note_id"            TEXT        NOT NULL,
+    "address"            bytea       NOT NULL,
+    "type"               TEXT        NOT NULL,
+    "created_at"         timestamptz NOT NULL DEFAULT now(),
+
+    CONSTRAINT "reaction_pkey" PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX "idx_reaction_unique" ON "reaction" ("note_id", "address", "type");
+
+CREATE TABLE "tip"
+(
+    "id"                 bigint      GENERATED BY DEFAULT AS IDENTITY (INCREMENT 1 MINVALUE 0 START 0),
+    "transaction_hash"   bytea       NOT NULL,
+    "note_id"            TEXT        NOT NULL,
+    "from"               bytea       NOT NULL,
+    "to"                 bytea       NOT NULL,
+    "amount"             numeric     NOT NULL,
+    "created_at"         timestamptz NOT NULL DEFAULT now(),
+
+    CONSTRAINT "tip_pkey" PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX "idx_tip_transaction_hash" ON "tip" ("transaction_hash");
+CREATE INDEX "idx_tip_note_id" ON "tip" ("note_id");
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "tip";
+DROP TABLE "reaction";
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "note"
+    ADD COLUMN "expires_at"  timestamptz,
+    ADD COLUMN "archived_at" timestamptz;
+
+CREATE INDEX "idx_note_archived" ON "note" ("address", "id" DESC) WHERE "archived_at" IS NOT NULL;
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX "idx_note_archived";
+
+ALTER TABLE "note"
+    DROP COLUMN "expires_at",
+    DROP COLUMN "archived_at";
+-- +goose StatementEnd
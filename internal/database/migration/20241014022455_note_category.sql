@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX "idx_note_type" ON "note" ("type", "id" DESC);
+
+CREATE TABLE "note_tag"
+(
+    "note_id"            TEXT        NOT NULL,
+    "tag"                TEXT        NOT NULL,
+
+    CONSTRAINT "note_tag_pkey" PRIMARY KEY ("note_id", "tag")
+);
+
+CREATE INDEX "idx_note_tag_tag" ON "note_tag" ("tag");
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "note_tag";
+DROP INDEX "idx_note_type";
+-- +goose StatementEnd
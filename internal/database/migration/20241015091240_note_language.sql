@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "note"
+    ADD COLUMN "language" TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "note"
+    DROP COLUMN "language";
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "note"
+    ADD COLUMN "edited_at"  timestamptz,
+    ADD COLUMN "deleted_at" timestamptz;
+
+CREATE TABLE "note_revision"
+(
+    "id"                 bigint      GENERATED BY DEFAULT AS IDENTITY (INCREMENT 1 MINVALUE 0 START 0),
+    "note_id"            TEXT        NOT NULL,
+    "note"               TEXT        NOT NULL,
+    "created_at"         timestamptz NOT NULL DEFAULT now(),
+
+    CONSTRAINT "note_revision_pkey" PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_note_revision_note_id" ON "note_revision" ("note_id", "id");
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "note_revision";
+
+ALTER TABLE "note"
+    DROP COLUMN "edited_at",
+    DROP COLUMN "deleted_at";
+-- +goose StatementEnd
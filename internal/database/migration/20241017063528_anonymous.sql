@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "note"
+    ADD COLUMN "anonymous" boolean NOT NULL DEFAULT false;
+
+ALTER TABLE "reply"
+    ADD COLUMN "anonymous" boolean NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "reply"
+    DROP COLUMN "anonymous";
+
+ALTER TABLE "note"
+    DROP COLUMN "anonymous";
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "profile"
+(
+    "address"            bytea       NOT NULL,
+    "display_name"       TEXT        NOT NULL DEFAULT '',
+    "bio"                TEXT        NOT NULL DEFAULT '',
+    "avatar"             TEXT        NOT NULL DEFAULT '',
+    "created_at"         timestamptz NOT NULL DEFAULT now(),
+    "updated_at"         timestamptz NOT NULL DEFAULT now(),
+
+    CONSTRAINT "profile_pkey" PRIMARY KEY ("address")
+);
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "profile";
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "circle"
+(
+    "id"                 bigint      GENERATED BY DEFAULT AS IDENTITY (INCREMENT 1 MINVALUE 0 START 0),
+    "circle_id"          TEXT        NOT NULL,
+    "name"               TEXT        NOT NULL,
+    "owner"              bytea       NOT NULL,
+    "created_at"         timestamptz NOT NULL DEFAULT now(),
+
+    CONSTRAINT "circle_pkey" PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX "idx_circle_circle_id" ON "circle" ("circle_id");
+
+CREATE TABLE "circle_member"
+(
+    "circle_id"          TEXT        NOT NULL,
+    "address"            bytea       NOT NULL,
+    "status"             TEXT        NOT NULL,
+    "invited_by"         bytea       NOT NULL,
+    "joined_at"          timestamptz,
+    "created_at"         timestamptz NOT NULL DEFAULT now(),
+
+    CONSTRAINT "circle_member_pkey" PRIMARY KEY ("circle_id", "address")
+);
+
+CREATE INDEX "idx_circle_member_address" ON "circle_member" ("address");
+
+ALTER TABLE "note"
+    ADD COLUMN "circle_id" TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "note"
+    DROP COLUMN "circle_id";
+
+DROP TABLE "circle_member";
+DROP TABLE "circle";
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "note"
+    ADD COLUMN "answered_at"     timestamptz,
+    ADD COLUMN "testimony"       TEXT NOT NULL DEFAULT '',
+    ADD COLUMN "answer_bonus_at" timestamptz;
+
+CREATE INDEX "idx_note_answered_at" ON "note" ("answered_at" DESC) WHERE "answered_at" IS NOT NULL;
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX "idx_note_answered_at";
+
+ALTER TABLE "note"
+    DROP COLUMN "answer_bonus_at",
+    DROP COLUMN "testimony",
+    DROP COLUMN "answered_at";
+-- +goose StatementEnd
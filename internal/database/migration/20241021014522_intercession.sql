@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "intercession"
+(
+    "note_id"            TEXT        NOT NULL,
+    "address"            bytea       NOT NULL,
+    "created_at"         timestamptz NOT NULL DEFAULT now(),
+
+    CONSTRAINT "intercession_pkey" PRIMARY KEY ("note_id", "address")
+);
+
+ALTER TABLE "note"
+    ADD COLUMN "intercessions" bigint NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "note"
+    DROP COLUMN "intercessions";
+
+DROP TABLE "intercession";
+-- +goose StatementEnd
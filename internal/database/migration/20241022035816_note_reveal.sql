@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "note"
+    ADD COLUMN "reveal_at" timestamptz;
+
+CREATE INDEX "idx_note_sealed" ON "note" ("address", "reveal_at") WHERE "reveal_at" IS NOT NULL;
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX "idx_note_sealed";
+
+ALTER TABLE "note"
+    DROP COLUMN "reveal_at";
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "note"
+    ADD COLUMN "recipient" bytea;
+
+CREATE INDEX "idx_note_recipient" ON "note" ("recipient") WHERE "recipient" IS NOT NULL;
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX "idx_note_recipient";
+
+ALTER TABLE "note"
+    DROP COLUMN "recipient";
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "encryption_key"
+(
+    "address"            bytea       NOT NULL,
+    "public_key"         bytea       NOT NULL,
+    "created_at"         timestamptz NOT NULL DEFAULT now(),
+    "updated_at"         timestamptz NOT NULL DEFAULT now(),
+
+    CONSTRAINT "encryption_key_pkey" PRIMARY KEY ("address")
+);
+
+CREATE TABLE "encrypted_note"
+(
+    "id"                 bigint      GENERATED BY DEFAULT AS IDENTITY (INCREMENT 1 MINVALUE 0 START 0),
+    "message_id"         TEXT        NOT NULL,
+    "address"            bytea       NOT NULL,
+    "recipient"          bytea       NOT NULL,
+    "public_key"         bytea       NOT NULL,
+    "ciphertext"         bytea       NOT NULL,
+    "created_at"         timestamptz NOT NULL DEFAULT now(),
+
+    CONSTRAINT "encrypted_note_pkey" PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX "idx_encrypted_note_message_id" ON "encrypted_note" ("message_id");
+CREATE INDEX "idx_encrypted_note_recipient" ON "encrypted_note" ("recipient", "id" DESC);
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "encrypted_note";
+DROP TABLE "encryption_key";
+-- +goose StatementEnd
@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "note"
+    ADD COLUMN "kind"               TEXT        NOT NULL DEFAULT 'note',
+    ADD COLUMN "chain_length"       INT         NOT NULL DEFAULT 0,
+    ADD COLUMN "chain_status"       TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN "chain_holder"       bytea,
+    ADD COLUMN "chain_assigned_at"  timestamptz,
+    ADD COLUMN "chain_waiting_at"   timestamptz,
+    ADD COLUMN "chain_completed_at" timestamptz;
+
+CREATE INDEX "idx_note_chain_waiting" ON "note" ("chain_waiting_at") WHERE "kind" = 'chain' AND "chain_status" <> 'completed';
+CREATE INDEX "idx_note_chain_holder" ON "note" ("chain_holder") WHERE "chain_holder" IS NOT NULL;
+
+CREATE TABLE "chain_link"
+(
+    "chain_id"           TEXT        NOT NULL,
+    "position"           INT         NOT NULL,
+    "address"            bytea       NOT NULL,
+    "line"               TEXT        NOT NULL,
+    "created_at"         timestamptz NOT NULL DEFAULT now(),
+
+    CONSTRAINT "chain_link_pkey" PRIMARY KEY ("chain_id", "position")
+);
+
+CREATE UNIQUE INDEX "idx_chain_link_address" ON "chain_link" ("chain_id", "address");
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "chain_link";
+
+DROP INDEX "idx_note_chain_holder";
+DROP INDEX "idx_note_chain_waiting";
+
+ALTER TABLE "note"
+    DROP COLUMN "chain_completed_at",
+    DROP COLUMN "chain_waiting_at",
+    DROP COLUMN "chain_assigned_at",
+    DROP COLUMN "chain_holder",
+    DROP COLUMN "chain_status",
+    DROP COLUMN "chain_length",
+    DROP COLUMN "kind";
+-- +goose StatementEnd
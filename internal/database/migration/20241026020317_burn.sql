@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "burn"
+(
+    "id"                 bigint      GENERATED BY DEFAULT AS IDENTITY (INCREMENT 1 MINVALUE 0 START 0),
+    "transaction_hash"   bytea       NOT NULL,
+    "purpose"            TEXT        NOT NULL,
+    "address"            bytea       NOT NULL,
+    "amount"             numeric     NOT NULL,
+    "created_at"         timestamptz NOT NULL DEFAULT now(),
+
+    CONSTRAINT "burn_pkey" PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX "idx_burn_transaction_hash" ON "burn" ("transaction_hash");
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "burn";
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "reply"
+    ADD COLUMN "verified" boolean NOT NULL DEFAULT false;
+
+CREATE TABLE "pending_mint"
+(
+    "id"                 bigint      GENERATED BY DEFAULT AS IDENTITY (INCREMENT 1 MINVALUE 0 START 0),
+    "address"            bytea       NOT NULL,
+    "amount"             numeric     NOT NULL,
+    "reason"             TEXT        NOT NULL,
+    "attempts"           INT         NOT NULL DEFAULT 0,
+    "last_error"         TEXT        NOT NULL DEFAULT '',
+    "transaction_hash"   bytea,
+    "minted_at"          timestamptz,
+    "created_at"         timestamptz NOT NULL DEFAULT now(),
+    "updated_at"         timestamptz NOT NULL DEFAULT now(),
+
+    CONSTRAINT "pending_mint_pkey" PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_pending_mint_waiting" ON "pending_mint" ("id") WHERE "minted_at" IS NULL;
+
+CREATE TABLE "pending_mint_transaction"
+(
+    "pending_mint_id"    bigint      NOT NULL,
+    "transaction_hash"   bytea       NOT NULL,
+    "created_at"         timestamptz NOT NULL DEFAULT now(),
+
+    CONSTRAINT "pending_mint_transaction_pkey" PRIMARY KEY ("pending_mint_id", "transaction_hash")
+);
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "pending_mint_transaction";
+DROP TABLE "pending_mint";
+
+ALTER TABLE "reply"
+    DROP COLUMN "verified";
+-- +goose StatementEnd
@@ -0,0 +1,130 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/internal/database/table"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (c *Client) SavePendingMint(ctx context.Context, data *schema.PendingMint) error {
+	var mint table.PendingMint
+
+	if err := mint.Import(data); err != nil {
+		return err
+	}
+
+	return c.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&mint).Error; err != nil {
+			return err
+		}
+
+		data.ID = mint.ID
+
+		for _, transactionHash := range data.TransactionHashes {
+			if err := saveMintTransaction(tx, mint.ID, transactionHash); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// FindPendingMints returns the oldest mints still waiting to be retried that have attempts left, along with the transactions sent for them.
+func (c *Client) FindPendingMints(ctx context.Context, maxAttempts, limit int) ([]*schema.PendingMint, error) {
+	var mints []table.PendingMint
+
+	err := c.database.WithContext(ctx).
+		Where("minted_at IS NULL AND attempts < ?", maxAttempts).
+		Order("id").
+		Limit(limit).
+		Find(&mints).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*schema.PendingMint, 0, len(mints))
+	index := make(map[uint64]*schema.PendingMint, len(mints))
+
+	for _, mint := range mints {
+		data, err := mint.Export()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, data)
+		index[data.ID] = data
+	}
+
+	if len(result) == 0 {
+		return result, nil
+	}
+
+	var transactions []table.PendingMintTransaction
+
+	ids := make([]uint64, 0, len(result))
+	for _, data := range result {
+		ids = append(ids, data.ID)
+	}
+
+	if err := c.database.WithContext(ctx).Where("pending_mint_id IN ?", ids).Order("created_at").Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+
+	for _, transaction := range transactions {
+		index[transaction.PendingMintID].TransactionHashes = append(index[transaction.PendingMintID].TransactionHashes, transaction.TransactionHash)
+	}
+
+	return result, nil
+}
+
+// CompletePendingMint records the transaction that finally minted a pending mint.
+func (c *Client) CompletePendingMint(ctx context.Context, id uint64, transactionHash common.Hash) error {
+	return c.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := saveMintTransaction(tx, id, transactionHash); err != nil {
+			return err
+		}
+
+		return tx.Model((*table.PendingMint)(nil)).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"attempts":         gorm.Expr("attempts + 1"),
+				"transaction_hash": transactionHash,
+				"minted_at":        time.Now(),
+				"updated_at":       time.Now(),
+			}).Error
+	})
+}
+
+// FailPendingMint counts another failed attempt of a pending mint, along with the transaction it sent if it got that far.
+// Earlier transactions are kept, any of them may still be mined.
+func (c *Client) FailPendingMint(ctx context.Context, id uint64, transactionHash common.Hash, reason string) error {
+	return c.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if transactionHash != (common.Hash{}) {
+			if err := saveMintTransaction(tx, id, transactionHash); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model((*table.PendingMint)(nil)).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"attempts":   gorm.Expr("attempts + 1"),
+				"last_error": reason,
+				"updated_at": time.Now(),
+			}).Error
+	})
+}
+
+func saveMintTransaction(tx *gorm.DB, id uint64, transactionHash common.Hash) error {
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&table.PendingMintTransaction{
+		PendingMintID:   id,
+		TransactionHash: transactionHash,
+		CreatedAt:       time.Now(),
+	}).Error
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/internal/database/table"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (c *Client) FindProfile(ctx context.Context, address common.Address) (*schema.Profile, error) {
+	var profile table.Profile
+
+	if err := c.database.WithContext(ctx).First(&profile, "address = ?", address).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrorRowNotFound
+		}
+
+		return nil, err
+	}
+
+	return profile.Export()
+}
+
+// FindProfiles returns the profiles of the given addresses keyed by address, addresses without a profile are left out.
+func (c *Client) FindProfiles(ctx context.Context, addresses []common.Address) (map[common.Address]*schema.Profile, error) {
+	result := make(map[common.Address]*schema.Profile, len(addresses))
+
+	if len(addresses) == 0 {
+		return result, nil
+	}
+
+	var profiles []table.Profile
+
+	if err := c.database.WithContext(ctx).Where("address IN ?", addresses).Find(&profiles).Error; err != nil {
+		return nil, err
+	}
+
+	for _, profile := range profiles {
+		data, err := profile.Export()
+		if err != nil {
+			return nil, err
+		}
+
+		result[data.Address] = data
+	}
+
+	return result, nil
+}
+
+// SaveProfile creates or replaces the profile of an address.
+func (c *Client) SaveProfile(ctx context.Context, data *schema.Profile) error {
+	var profile table.Profile
+
+	if err := profile.Import(data); err != nil {
+		return err
+	}
+
+	profile.UpdatedAt = time.Now()
+
+	err := c.database.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "address"}},
+		DoUpdates: clause.AssignmentColumns([]string{"display_name", "bio", "avatar", "updated_at"}),
+	}).Create(&profile).Error
+	if err != nil {
+		return err
+	}
+
+	data.UpdatedAt = profile.UpdatedAt.Unix()
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/brucexc/pray-to-earn/internal/database/table"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SaveReaction stores a reaction and reports whether it is new, reacting twice with the same type is a no-op.
+func (c *Client) SaveReaction(ctx context.Context, data *schema.Reaction) (bool, error) {
+	var reaction table.Reaction
+
+	if err := reaction.Import(data); err != nil {
+		return false, err
+	}
+
+	result := c.database.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&reaction)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (c *Client) CountReactions(ctx context.Context, noteID string) (map[string]int64, error) {
+	var rows []struct {
+		Type  string `gorm:"column:type"`
+		Count int64  `gorm:"column:count"`
+	}
+
+	err := c.database.WithContext(ctx).
+		Model((*table.Reaction)(nil)).
+		Select("type, COUNT(*) AS count").
+		Where("note_id = ?", noteID).
+		Group("type").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Type] = row.Count
+	}
+
+	return counts, nil
+}
+
+func (c *Client) FindTip(ctx context.Context, transactionHash common.Hash) (*schema.Tip, error) {
+	var tip table.Tip
+
+	if err := c.database.WithContext(ctx).First(&tip, "transaction_hash = ?", transactionHash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrorRowNotFound
+		}
+
+		return nil, err
+	}
+
+	return tip.Export()
+}
+
+func (c *Client) SaveTip(ctx context.Context, data *schema.Tip) error {
+	var tip table.Tip
+
+	if err := tip.Import(data); err != nil {
+		return err
+	}
+
+	if err := c.database.WithContext(ctx).Create(&tip).Error; err != nil {
+		return err
+	}
+
+	data.ID = tip.ID
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package table
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Burn struct {
+	ID              uint64         `gorm:"column:id;primaryKey"`
+	TransactionHash common.Hash    `gorm:"column:transaction_hash"`
+	Purpose         string         `gorm:"column:purpose"`
+	Address         common.Address `gorm:"column:address"`
+	Amount          string         `gorm:"column:amount"`
+	CreatedAt       time.Time      `gorm:"column:created_at"`
+}
+
+func (b *Burn) TableName() string {
+	return "burn"
+}
+
+func (b *Burn) Import(burn *schema.Burn) error {
+	b.ID = burn.ID
+	b.TransactionHash = burn.TransactionHash
+	b.Purpose = burn.Purpose
+	b.Address = burn.Address
+	b.Amount = burn.Amount.String()
+
+	return nil
+}
+
+func (b *Burn) Export() (*schema.Burn, error) {
+	amount, ok := new(big.Int).SetString(b.Amount, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid burn amount %s", b.Amount)
+	}
+
+	return &schema.Burn{
+		ID:              b.ID,
+		TransactionHash: b.TransactionHash,
+		Purpose:         b.Purpose,
+		Address:         b.Address,
+		Amount:          amount,
+		CreatedAt:       b.CreatedAt.Unix(),
+	}, nil
+}
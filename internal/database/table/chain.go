@@ -0,0 +1,51 @@
+package table
+
+import (
+	"time"
+
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ExportChain returns the chain state of a chain prayer note, its links are loaded separately.
+func (n *Note) ExportChain() (*schema.Chain, error) {
+	chain := schema.Chain{
+		ID:        n.MessageID,
+		Starter:   n.Address,
+		Length:    n.ChainLength,
+		Status:    n.ChainStatus,
+		Holder:    n.ChainHolder,
+		CreatedAt: n.CreatedAt.Unix(),
+	}
+
+	if n.ChainAssignedAt != nil {
+		chain.AssignedAt = n.ChainAssignedAt.Unix()
+	}
+
+	if n.ChainCompletedAt != nil {
+		chain.CompletedAt = n.ChainCompletedAt.Unix()
+	}
+
+	return &chain, nil
+}
+
+type ChainLink struct {
+	ChainID   string         `gorm:"column:chain_id;primaryKey"`
+	Position  int            `gorm:"column:position;primaryKey"`
+	Address   common.Address `gorm:"column:address"`
+	Line      string         `gorm:"column:line"`
+	CreatedAt time.Time      `gorm:"column:created_at"`
+}
+
+func (c *ChainLink) TableName() string {
+	return "chain_link"
+}
+
+func (c *ChainLink) Export() (*schema.ChainLink, error) {
+	return &schema.ChainLink{
+		Position:  c.Position,
+		Address:   c.Address,
+		Line:      c.Line,
+		CreatedAt: c.CreatedAt.Unix(),
+	}, nil
+}
@@ -0,0 +1,80 @@
+package table
+
+import (
+	"time"
+
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Circle struct {
+	ID        uint64         `gorm:"column:id;primaryKey"`
+	CircleID  string         `gorm:"column:circle_id"`
+	Name      string         `gorm:"column:name"`
+	Owner     common.Address `gorm:"column:owner"`
+	CreatedAt time.Time      `gorm:"column:created_at"`
+}
+
+func (c *Circle) TableName() string {
+	return "circle"
+}
+
+func (c *Circle) Import(circle *schema.Circle) error {
+	c.CircleID = circle.ID
+	c.Name = circle.Name
+	c.Owner = circle.Owner
+
+	return nil
+}
+
+func (c *Circle) Export() (*schema.Circle, error) {
+	return &schema.Circle{
+		ID:        c.CircleID,
+		Name:      c.Name,
+		Owner:     c.Owner,
+		CreatedAt: c.CreatedAt.Unix(),
+	}, nil
+}
+
+type CircleMember struct {
+	CircleID  string         `gorm:"column:circle_id;primaryKey"`
+	Address   common.Address `gorm:"column:address;primaryKey"`
+	Status    string         `gorm:"column:status"`
+	InvitedBy common.Address `gorm:"column:invited_by"`
+	JoinedAt  *time.Time     `gorm:"column:joined_at"`
+	CreatedAt time.Time      `gorm:"column:created_at"`
+}
+
+func (c *CircleMember) TableName() string {
+	return "circle_member"
+}
+
+func (c *CircleMember) Import(member *schema.CircleMember) error {
+	c.CircleID = member.CircleID
+	c.Address = member.Address
+	c.Status = member.Status
+	c.InvitedBy = member.InvitedBy
+
+	if member.JoinedAt > 0 {
+		joinedAt := time.Unix(member.JoinedAt, 0)
+		c.JoinedAt = &joinedAt
+	}
+
+	return nil
+}
+
+func (c *CircleMember) Export() (*schema.CircleMember, error) {
+	member := schema.CircleMember{
+		CircleID:  c.CircleID,
+		Address:   c.Address,
+		Status:    c.Status,
+		InvitedBy: c.InvitedBy,
+		CreatedAt: c.CreatedAt.Unix(),
+	}
+
+	if c.JoinedAt != nil {
+		member.JoinedAt = c.JoinedAt.Unix()
+	}
+
+	return &member, nil
+}
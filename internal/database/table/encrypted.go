@@ -0,0 +1,71 @@
+package table
+
+import (
+	"time"
+
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type EncryptionKey struct {
+	Address   common.Address `gorm:"column:address;primaryKey"`
+	PublicKey []byte         `gorm:"column:public_key"`
+	CreatedAt time.Time      `gorm:"column:created_at"`
+	UpdatedAt time.Time      `gorm:"column:updated_at"`
+}
+
+func (k *EncryptionKey) TableName() string {
+	return "encryption_key"
+}
+
+func (k *EncryptionKey) Import(key *schema.EncryptionKey) error {
+	k.Address = key.Address
+	k.PublicKey = key.PublicKey
+
+	return nil
+}
+
+func (k *EncryptionKey) Export() (*schema.EncryptionKey, error) {
+	return &schema.EncryptionKey{
+		Address:   k.Address,
+		PublicKey: k.PublicKey,
+		UpdatedAt: k.UpdatedAt.Unix(),
+	}, nil
+}
+
+type EncryptedNote struct {
+	ID         uint64         `gorm:"column:id;primaryKey"`
+	MessageID  string         `gorm:"column:message_id"`
+	Address    common.Address `gorm:"column:address"`
+	Recipient  common.Address `gorm:"column:recipient"`
+	PublicKey  []byte         `gorm:"column:public_key"`
+	Ciphertext []byte         `gorm:"column:ciphertext"`
+	CreatedAt  time.Time      `gorm:"column:created_at"`
+}
+
+func (n *EncryptedNote) TableName() string {
+	return "encrypted_note"
+}
+
+func (n *EncryptedNote) Import(note *schema.EncryptedNote) error {
+	n.ID = note.ID
+	n.MessageID = note.MessageID
+	n.Address = note.Address
+	n.Recipient = note.Recipient
+	n.PublicKey = note.PublicKey
+	n.Ciphertext = note.Ciphertext
+
+	return nil
+}
+
+func (n *EncryptedNote) Export() (*schema.EncryptedNote, error) {
+	return &schema.EncryptedNote{
+		ID:         n.ID,
+		MessageID:  n.MessageID,
+		Address:    n.Address,
+		Recipient:  n.Recipient,
+		PublicKey:  n.PublicKey,
+		Ciphertext: n.Ciphertext,
+		CreatedAt:  n.CreatedAt.Unix(),
+	}, nil
+}
@@ -0,0 +1,48 @@
+package table
+
+import (
+	"time"
+
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type InboxItem struct {
+	ID        uint64         `gorm:"column:id;primaryKey"`
+	Address   common.Address `gorm:"column:address"`
+	Type      string         `gorm:"column:type"`
+	NoteID    string         `gorm:"column:note_id"`
+	ReplyID   *uint64        `gorm:"column:reply_id"`
+	Actor     common.Address `gorm:"column:actor"`
+	Read      bool           `gorm:"column:read"`
+	CreatedAt time.Time      `gorm:"column:created_at"`
+}
+
+func (i *InboxItem) TableName() string {
+	return "inbox"
+}
+
+func (i *InboxItem) Import(item *schema.InboxItem) error {
+	i.ID = item.ID
+	i.Address = item.Address
+	i.Type = item.Type
+	i.NoteID = item.NoteID
+	i.ReplyID = item.ReplyID
+	i.Actor = item.Actor
+	i.Read = item.Read
+
+	return nil
+}
+
+func (i *InboxItem) Export() (*schema.InboxItem, error) {
+	return &schema.InboxItem{
+		ID:        i.ID,
+		Address:   i.Address,
+		Type:      i.Type,
+		NoteID:    i.NoteID,
+		ReplyID:   i.ReplyID,
+		Actor:     i.Actor,
+		Read:      i.Read,
+		CreatedAt: i.CreatedAt.Unix(),
+	}, nil
+}
@@ -0,0 +1,17 @@
+package table
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Intercession struct {
+	NoteID    string         `gorm:"column:note_id;primaryKey"`
+	Address   common.Address `gorm:"column:address;primaryKey"`
+	CreatedAt time.Time      `gorm:"column:created_at"`
+}
+
+func (i *Intercession) TableName() string {
+	return "intercession"
+}
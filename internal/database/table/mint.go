@@ -0,0 +1,74 @@
+package table
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type PendingMint struct {
+	ID              uint64         `gorm:"column:id;primaryKey"`
+	Address         common.Address `gorm:"column:address"`
+	Amount          string         `gorm:"column:amount"`
+	Reason          string         `gorm:"column:reason"`
+	Attempts        int            `gorm:"column:attempts"`
+	LastError       string         `gorm:"column:last_error"`
+	TransactionHash *common.Hash   `gorm:"column:transaction_hash"`
+	MintedAt        *time.Time     `gorm:"column:minted_at"`
+	CreatedAt       time.Time      `gorm:"column:created_at"`
+	UpdatedAt       time.Time      `gorm:"column:updated_at"`
+}
+
+func (p *PendingMint) TableName() string {
+	return "pending_mint"
+}
+
+// PendingMintTransaction is one mint transaction sent for a pending mint.
+type PendingMintTransaction struct {
+	PendingMintID   uint64      `gorm:"column:pending_mint_id;primaryKey"`
+	TransactionHash common.Hash `gorm:"column:transaction_hash;primaryKey"`
+	CreatedAt       time.Time   `gorm:"column:created_at"`
+}
+
+func (p *PendingMintTransaction) TableName() string {
+	return "pending_mint_transaction"
+}
+
+func (p *PendingMint) Import(mint *schema.PendingMint) error {
+	p.ID = mint.ID
+	p.Address = mint.Address
+	p.Amount = mint.Amount.String()
+	p.Reason = mint.Reason
+	p.Attempts = mint.Attempts
+	p.LastError = mint.LastError
+	p.TransactionHash = mint.TransactionHash
+
+	return nil
+}
+
+func (p *PendingMint) Export() (*schema.PendingMint, error) {
+	amount, ok := new(big.Int).SetString(p.Amount, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid pending mint amount %s", p.Amount)
+	}
+
+	mint := schema.PendingMint{
+		ID:              p.ID,
+		Address:         p.Address,
+		Amount:          amount,
+		Reason:          p.Reason,
+		Attempts:        p.Attempts,
+		LastError:       p.LastError,
+		TransactionHash: p.TransactionHash,
+		CreatedAt:       p.CreatedAt.Unix(),
+	}
+
+	if p.MintedAt != nil {
+		mint.MintedAt = p.MintedAt.Unix()
+	}
+
+	return &mint, nil
+}
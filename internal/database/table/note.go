@@ -8,10 +8,35 @@ import (
 )
 
 type Note struct {
-	ID        uint64         `gorm:"column:id;primaryKey"`
-	Address   common.Address `gorm:"column:address"`
-	Note      string         `gorm:"column:note"`
-	CreatedAt time.Time      `gorm:"column:created_at"`
+	ID             uint64          `gorm:"column:id;primaryKey"`
+	MessageID      string          `gorm:"column:message_id"`
+	Address        common.Address  `gorm:"column:address"`
+	Note           string          `gorm:"column:note"`
+	Category       string          `gorm:"column:type"`
+	Language       string          `gorm:"column:language"`
+	Hidden         bool            `gorm:"column:hidden"`
+	Anonymous      bool            `gorm:"column:anonymous"`
+	Circle         string          `gorm:"column:circle_id"`
+	Recipient      *common.Address `gorm:"column:recipient"`
+	SearchLanguage string          `gorm:"column:search_language;default:simple"`
+	RevealAt       *time.Time      `gorm:"column:reveal_at"`
+	ExpiresAt      *time.Time      `gorm:"column:expires_at"`
+	ArchivedAt     *time.Time      `gorm:"column:archived_at"`
+	EditedAt       *time.Time      `gorm:"column:edited_at"`
+	AnsweredAt     *time.Time      `gorm:"column:answered_at"`
+	Testimony      string          `gorm:"column:testimony"`
+	Intercessions  int64           `gorm:"column:intercessions"`
+	Kind           string          `gorm:"column:kind;default:note"`
+	DeletedAt      *time.Time      `gorm:"column:deleted_at"`
+	CreatedAt      time.Time       `gorm:"column:created_at"`
+
+	// the chain columns are only set on chain prayers
+	ChainLength      int             `gorm:"column:chain_length"`
+	ChainStatus      string          `gorm:"column:chain_status"`
+	ChainHolder      *common.Address `gorm:"column:chain_holder"`
+	ChainAssignedAt  *time.Time      `gorm:"column:chain_assigned_at"`
+	ChainWaitingAt   *time.Time      `gorm:"column:chain_waiting_at"`
+	ChainCompletedAt *time.Time      `gorm:"column:chain_completed_at"`
 }
 
 func (n *Note) TableName() string {
@@ -20,17 +45,74 @@ func (n *Note) TableName() string {
 
 func (n *Note) Import(note *schema.Note) error {
 	n.ID = note.ID
+	n.MessageID = note.MessageID
 	n.Address = note.Address
 	n.Note = note.Note
+	n.Category = note.Category
+	n.Language = note.Language
+	n.Hidden = note.Hidden
+	n.Anonymous = note.Anonymous
+	n.Circle = note.Circle
+	n.Recipient = note.Recipient
+	n.SearchLanguage = note.SearchLanguage
+	n.Kind = note.Kind
+
+	if note.RevealAt > 0 {
+		revealAt := time.Unix(note.RevealAt, 0)
+		n.RevealAt = &revealAt
+	}
+
+	if note.ExpiresAt > 0 {
+		expiresAt := time.Unix(note.ExpiresAt, 0)
+		n.ExpiresAt = &expiresAt
+	}
+
+	if note.ArchivedAt > 0 {
+		archivedAt := time.Unix(note.ArchivedAt, 0)
+		n.ArchivedAt = &archivedAt
+	}
 
 	return nil
 }
 
 func (n *Note) Export() (*schema.Note, error) {
-	return &schema.Note{
-		ID:        n.ID,
-		Address:   n.Address,
-		Note:      n.Note,
-		CreatedAt: n.CreatedAt.Unix(),
-	}, nil
+	note := schema.Note{
+		ID:             n.ID,
+		MessageID:      n.MessageID,
+		Address:        n.Address,
+		Note:           n.Note,
+		Category:       n.Category,
+		Language:       n.Language,
+		Hidden:         n.Hidden,
+		Anonymous:      n.Anonymous,
+		Circle:         n.Circle,
+		Recipient:      n.Recipient,
+		Testimony:      n.Testimony,
+		Intercessions:  n.Intercessions,
+		Kind:           n.Kind,
+		CreatedAt:      n.CreatedAt.Unix(),
+		SearchLanguage: n.SearchLanguage,
+	}
+
+	if n.RevealAt != nil {
+		note.RevealAt = n.RevealAt.Unix()
+	}
+
+	if n.ExpiresAt != nil {
+		note.ExpiresAt = n.ExpiresAt.Unix()
+	}
+
+	if n.ArchivedAt != nil {
+		note.ArchivedAt = n.ArchivedAt.Unix()
+	}
+
+	if n.EditedAt != nil {
+		note.EditedAt = n.EditedAt.Unix()
+	}
+
+	if n.AnsweredAt != nil {
+		note.AnsweredAt = n.AnsweredAt.Unix()
+	}
+
+	return &note, nil
 }
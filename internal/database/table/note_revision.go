@@ -0,0 +1,27 @@
+package table
+
+import (
+	"time"
+
+	"github.com/brucexc/pray-to-earn/schema"
+)
+
+type NoteRevision struct {
+	ID        uint64    `gorm:"column:id;primaryKey"`
+	NoteID    string    `gorm:"column:note_id"`
+	Note      string    `gorm:"column:note"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (n *NoteRevision) TableName() string {
+	return "note_revision"
+}
+
+func (n *NoteRevision) Export() (*schema.NoteRevision, error) {
+	return &schema.NoteRevision{
+		ID:        n.ID,
+		NoteID:    n.NoteID,
+		Note:      n.Note,
+		CreatedAt: n.CreatedAt.Unix(),
+	}, nil
+}
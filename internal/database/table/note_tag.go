@@ -0,0 +1,10 @@
+package table
+
+type NoteTag struct {
+	NoteID string `gorm:"column:note_id;primaryKey"`
+	Tag    string `gorm:"column:tag;primaryKey"`
+}
+
+func (n *NoteTag) TableName() string {
+	return "note_tag"
+}
@@ -0,0 +1,40 @@
+package table
+
+import (
+	"time"
+
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Profile struct {
+	Address     common.Address `gorm:"column:address;primaryKey"`
+	DisplayName string         `gorm:"column:display_name"`
+	Bio         string         `gorm:"column:bio"`
+	Avatar      string         `gorm:"column:avatar"`
+	CreatedAt   time.Time      `gorm:"column:created_at"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at"`
+}
+
+func (p *Profile) TableName() string {
+	return "profile"
+}
+
+func (p *Profile) Import(profile *schema.Profile) error {
+	p.Address = profile.Address
+	p.DisplayName = profile.DisplayName
+	p.Bio = profile.Bio
+	p.Avatar = profile.Avatar
+
+	return nil
+}
+
+func (p *Profile) Export() (*schema.Profile, error) {
+	return &schema.Profile{
+		Address:     p.Address,
+		DisplayName: p.DisplayName,
+		Bio:         p.Bio,
+		Avatar:      p.Avatar,
+		UpdatedAt:   p.UpdatedAt.Unix(),
+	}, nil
+}
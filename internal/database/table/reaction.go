@@ -0,0 +1,39 @@
+package table
+
+import (
+	"time"
+
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Reaction struct {
+	ID        uint64         `gorm:"column:id;primaryKey"`
+	NoteID    string         `gorm:"column:note_id"`
+	Address   common.Address `gorm:"column:address"`
+	Type      string         `gorm:"column:type"`
+	CreatedAt time.Time      `gorm:"column:created_at"`
+}
+
+func (r *Reaction) TableName() string {
+	return "reaction"
+}
+
+func (r *Reaction) Import(reaction *schema.Reaction) error {
+	r.ID = reaction.ID
+	r.NoteID = reaction.NoteID
+	r.Address = reaction.Address
+	r.Type = reaction.Type
+
+	return nil
+}
+
+func (r *Reaction) Export() (*schema.Reaction, error) {
+	return &schema.Reaction{
+		ID:        r.ID,
+		NoteID:    r.NoteID,
+		Address:   r.Address,
+		Type:      r.Type,
+		CreatedAt: r.CreatedAt.Unix(),
+	}, nil
+}
@@ -0,0 +1,45 @@
+package table
+
+import (
+	"time"
+
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Reply struct {
+	ID        uint64         `gorm:"column:id;primaryKey"`
+	NoteID    string         `gorm:"column:note_id"`
+	Address   common.Address `gorm:"column:address"`
+	Reply     string         `gorm:"column:reply"`
+	Anonymous bool           `gorm:"column:anonymous"`
+	Verified  bool           `gorm:"column:verified"`
+	CreatedAt time.Time      `gorm:"column:created_at"`
+}
+
+func (r *Reply) TableName() string {
+	return "reply"
+}
+
+func (r *Reply) Import(reply *schema.Reply) error {
+	r.ID = reply.ID
+	r.NoteID = reply.NoteID
+	r.Address = reply.Address
+	r.Reply = reply.Reply
+	r.Anonymous = reply.Anonymous
+	r.Verified = reply.Verified
+
+	return nil
+}
+
+func (r *Reply) Export() (*schema.Reply, error) {
+	return &schema.Reply{
+		ID:        r.ID,
+		NoteID:    r.NoteID,
+		Address:   r.Address,
+		Reply:     r.Reply,
+		Anonymous: r.Anonymous,
+		Verified:  r.Verified,
+		CreatedAt: r.CreatedAt.Unix(),
+	}, nil
+}
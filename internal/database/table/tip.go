@@ -0,0 +1,52 @@
+package table
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Tip struct {
+	ID              uint64         `gorm:"column:id;primaryKey"`
+	TransactionHash common.Hash    `gorm:"column:transaction_hash"`
+	NoteID          string         `gorm:"column:note_id"`
+	From            common.Address `gorm:"column:from"`
+	To              common.Address `gorm:"column:to"`
+	Amount          string         `gorm:"column:amount"`
+	CreatedAt       time.Time      `gorm:"column:created_at"`
+}
+
+func (t *Tip) TableName() string {
+	return "tip"
+}
+
+func (t *Tip) Import(tip *schema.Tip) error {
+	t.ID = tip.ID
+	t.TransactionHash = tip.TransactionHash
+	t.NoteID = tip.NoteID
+	t.From = tip.From
+	t.To = tip.To
+	t.Amount = tip.Amount.String()
+
+	return nil
+}
+
+func (t *Tip) Export() (*schema.Tip, error) {
+	amount, ok := new(big.Int).SetString(t.Amount, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid tip amount %s", t.Amount)
+	}
+
+	return &schema.Tip{
+		ID:              t.ID,
+		TransactionHash: t.TransactionHash,
+		NoteID:          t.NoteID,
+		From:            t.From,
+		To:              t.To,
+		Amount:          amount,
+		CreatedAt:       t.CreatedAt.Unix(),
+	}, nil
+}
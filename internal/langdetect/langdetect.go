@@ -0,0 +1,225 @@
+// Package langdetect guesses the language of short texts without any network access.
+// Texts in a distinctive script are classified by script, Latin texts are ranked against
+// character n-gram profiles built from the embedded sample corpus (Cavnar and Trenkle).
+package langdetect
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+//go:embed corpus/*.txt
+var corpusFS embed.FS
+
+const (
+	maxGramSize = 3
+	profileSize = 300
+	// minLetters is the shortest text worth classifying, anything shorter is reported as unknown.
+	minLetters = 8
+)
+
+// scripts maps writing systems used by a single language in practice to that language.
+var scripts = []struct {
+	table    *unicode.RangeTable
+	language string
+}{
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Hangul, "ko"},
+	{unicode.Han, "zh"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Arabic, "ar"},
+	{unicode.Hebrew, "he"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Thai, "th"},
+	{unicode.Greek, "el"},
+}
+
+type profile map[string]int
+
+var profiles = mustLoadProfiles()
+
+// Languages returns the ISO 639-1 codes the detector can return.
+func Languages() []string {
+	languages := make([]string, 0, len(profiles)+len(scripts))
+
+	for language := range profiles {
+		languages = append(languages, language)
+	}
+
+	for _, script := range scripts {
+		if !slices.Contains(languages, script.language) {
+			languages = append(languages, script.language)
+		}
+	}
+
+	sort.Strings(languages)
+
+	return languages
+}
+
+// Detect returns the ISO 639-1 code of the most likely language, or an empty string if the text is too short to tell.
+func Detect(text string) string {
+	var (
+		letters int
+		counts  = make(map[string]int)
+		latin   int
+	)
+
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+
+		letters++
+
+		if unicode.Is(unicode.Latin, r) {
+			latin++
+
+			continue
+		}
+
+		for _, script := range scripts {
+			if unicode.Is(script.table, r) {
+				counts[script.language]++
+
+				break
+			}
+		}
+	}
+
+	if letters == 0 {
+		return ""
+	}
+
+	// kana mixed with kanji is still japanese, so it wins over han whenever present
+	if counts["ja"] > 0 {
+		return "ja"
+	}
+
+	var (
+		best      string
+		bestCount int
+	)
+
+	for language, count := range counts {
+		if count > bestCount {
+			best, bestCount = language, count
+		}
+	}
+
+	if bestCount > latin {
+		return best
+	}
+
+	if latin < minLetters {
+		return ""
+	}
+
+	return detectLatin(text)
+}
+
+func detectLatin(text string) string {
+	document := buildProfile(text)
+
+	var (
+		best     string
+		distance = -1
+	)
+
+	for language, reference := range profiles {
+		current := 0
+
+		for gram, rank := range document {
+			referenceRank, found := reference[gram]
+			if !found {
+				current += profileSize
+
+				continue
+			}
+
+			if referenceRank > rank {
+				current += referenceRank - rank
+			} else {
+				current += rank - referenceRank
+			}
+		}
+
+		if distance < 0 || current < distance || (current == distance && language < best) {
+			best, distance = language, current
+		}
+	}
+
+	return best
+}
+
+// buildProfile ranks the most frequent 1 to 3 character grams of the text, words are padded with spaces.
+func buildProfile(text string) profile {
+	frequencies := make(map[string]int)
+
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	}) {
+		runes := []rune(" " + word + " ")
+
+		for size := 1; size <= maxGramSize; size++ {
+			for i := 0; i+size <= len(runes); i++ {
+				gram := string(runes[i : i+size])
+				if gram == " " {
+					continue
+				}
+
+				frequencies[gram]++
+			}
+		}
+	}
+
+	grams := make([]string, 0, len(frequencies))
+	for gram := range frequencies {
+		grams = append(grams, gram)
+	}
+
+	sort.Slice(grams, func(i, j int) bool {
+		if frequencies[grams[i]] != frequencies[grams[j]] {
+			return frequencies[grams[i]] > frequencies[grams[j]]
+		}
+
+		return grams[i] < grams[j]
+	})
+
+	if len(grams) > profileSize {
+		grams = grams[:profileSize]
+	}
+
+	result := make(profile, len(grams))
+	for rank, gram := range grams {
+		result[gram] = rank
+	}
+
+	return result
+}
+
+func mustLoadProfiles() map[string]profile {
+	entries, err := corpusFS.ReadDir("corpus")
+	if err != nil {
+		panic(fmt.Errorf("read corpus: %w", err))
+	}
+
+	result := make(map[string]profile, len(entries))
+
+	for _, entry := range entries {
+		content, err := corpusFS.ReadFile(path.Join("corpus", entry.Name()))
+		if err != nil {
+			panic(fmt.Errorf("read corpus %s: %w", entry.Name(), err))
+		}
+
+		result[strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))] = buildProfile(string(content))
+	}
+
+	return result
+}
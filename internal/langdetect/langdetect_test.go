@@ -0,0 +1,59 @@
+package langdetect
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "empty", text: "", want: ""},
+		{name: "emoji only", text: "🙏🙏🙏", want: ""},
+		{name: "short latin", text: "amen", want: ""},
+		{name: "english", text: "Please pray for my mother, she is in the hospital and we are worried about her.", want: "en"},
+		{name: "german", text: "Bitte betet für meine Mutter, sie liegt im Krankenhaus und wir machen uns große Sorgen.", want: "de"},
+		{name: "spanish", text: "Por favor oren por mi madre, está en el hospital y estamos muy preocupados por ella.", want: "es"},
+		{name: "french", text: "Priez pour ma mère s'il vous plaît, elle est à l'hôpital et nous sommes très inquiets.", want: "fr"},
+		{name: "japanese kana and kanji", text: "母のために祈ってください", want: "ja"},
+		{name: "chinese", text: "请为我的母亲祈祷", want: "zh"},
+		{name: "korean", text: "어머니를 위해 기도해 주세요", want: "ko"},
+		{name: "russian", text: "Пожалуйста, помолитесь за мою маму", want: "ru"},
+		{name: "script beats a few latin letters", text: "Пожалуйста, помолитесь OK", want: "ru"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Detect(tt.text); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	t.Parallel()
+
+	languages := Languages()
+
+	if !slices.IsSorted(languages) {
+		t.Errorf("got %v, want sorted codes", languages)
+	}
+
+	for _, want := range []string{"en", "de", "ja", "zh", "ru"} {
+		if !slices.Contains(languages, want) {
+			t.Errorf("got %v, want it to contain %s", languages, want)
+		}
+	}
+
+	if len(slices.Compact(slices.Clone(languages))) != len(languages) {
+		t.Errorf("got %v, want no duplicates", languages)
+	}
+}
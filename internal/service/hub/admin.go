@@ -0,0 +1,129 @@
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/brucexc/pray-to-earn/internal/database"
+	"github.com/brucexc/pray-to-earn/internal/service/hub/model/errorx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type GetNoteRevisionsRequest struct {
+	ID string `param:"id" validate:"required"`
+}
+
+type GetNoteAuthorRequest struct {
+	ID string `param:"id" validate:"required"`
+	// ReplyID asks for the author of one reply under the note instead.
+	ReplyID *uint64 `query:"reply_id"`
+}
+
+type GetNoteAuthorResponse struct {
+	Address   common.Address `json:"address"`
+	Anonymous bool           `json:"anonymous"`
+}
+
+func (h *Hub) GetNoteRevisions(c echo.Context) error {
+	var request GetNoteRevisionsRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	if !h.isModerator(address) {
+		return errorx.ForbiddenError(c, fmt.Errorf("%s is not a moderator", address.Hex()))
+	}
+
+	revisions, err := h.databaseClient.FindNoteRevisions(c.Request().Context(), request.ID)
+	if err != nil {
+		zap.L().Error("failed to find note revisions", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	zap.L().Info("moderator read note revisions", zap.String("moderator", address.Hex()), zap.String("id", request.ID))
+
+	return c.JSON(http.StatusOK, Response{
+		Data: revisions,
+	})
+}
+
+// GetNoteAuthor recovers the wallet behind a note or reply, it is the only way to see through a pseudonym.
+func (h *Hub) GetNoteAuthor(c echo.Context) error {
+	var request GetNoteAuthorRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	if !h.isModerator(address) {
+		return errorx.ForbiddenError(c, fmt.Errorf("%s is not a moderator", address.Hex()))
+	}
+
+	ctx := c.Request().Context()
+
+	var response GetNoteAuthorResponse
+
+	if request.ReplyID != nil {
+		reply, err := h.databaseClient.FindReply(ctx, request.ID, *request.ReplyID)
+		if err != nil {
+			if errors.Is(err, database.ErrorRowNotFound) {
+				return errorx.NotFoundError(c, fmt.Errorf("reply %d not found", *request.ReplyID))
+			}
+
+			zap.L().Error("failed to find reply", zap.Uint64("id", *request.ReplyID), zap.Error(err))
+
+			return errorx.InternalError(c)
+		}
+
+		response = GetNoteAuthorResponse{Address: reply.Address, Anonymous: reply.Anonymous}
+	} else {
+		// withdrawn notes are exactly the ones moderators need to trace
+		note, err := h.databaseClient.FindNoteForModeration(ctx, request.ID)
+		if err != nil {
+			if errors.Is(err, database.ErrorRowNotFound) {
+				return errorx.NotFoundError(c, fmt.Errorf("note %s not found", request.ID))
+			}
+
+			zap.L().Error("failed to find note", zap.String("id", request.ID), zap.Error(err))
+
+			return errorx.InternalError(c)
+		}
+
+		response = GetNoteAuthorResponse{Address: note.Address, Anonymous: note.Anonymous}
+	}
+
+	// every disclosure is logged so moderator access can be audited
+	zap.L().Info("moderator recovered note author", zap.String("moderator", address.Hex()), zap.String("id", request.ID), zap.Any("reply_id", request.ReplyID))
+
+	return c.JSON(http.StatusOK, Response{
+		Data: response,
+	})
+}
+
+func (h *Hub) isModerator(address common.Address) bool {
+	return slices.Contains(h.config.Moderation.Moderators, address)
+}
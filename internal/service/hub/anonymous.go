@@ -0,0 +1,40 @@
+package hub
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const pseudonymPrefix = "anon-"
+
+// pseudonym returns the name an anonymous author goes by under one note, it is stable for that note but unrelated across notes.
+func (h *Hub) pseudonym(address common.Address, noteID string) string {
+	secret := h.config.Anonymity.Secret
+	if secret == "" {
+		secret = h.config.AdminKey
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(address.Bytes())
+	mac.Write([]byte(noteID))
+
+	return pseudonymPrefix + hex.EncodeToString(mac.Sum(nil))[:8]
+}
+
+// authorLabel returns the full address only when it is allowed to be disclosed, otherwise the name shown to readers.
+// Display names come from names, which may be nil, anonymous authors always go by their pseudonym.
+func (h *Hub) authorLabel(names map[common.Address]string, address common.Address, noteID string, anonymous, full bool) string {
+	switch {
+	case full:
+		return address.Hex()
+	case anonymous:
+		return h.pseudonym(address, noteID)
+	case names[address] != "":
+		return names[address]
+	default:
+		return address.Hex()[:8]
+	}
+}
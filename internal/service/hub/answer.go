@@ -0,0 +1,286 @@
+package hub
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/brucexc/pray-to-earn/internal/service/hub/model/errorx"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/creasty/defaults"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type AnswerNoteRequest struct {
+	ID        string `param:"id" validate:"required"`
+	Testimony string `json:"testimony"`
+}
+
+type AnswerNoteResponse struct {
+	Note  NoteView     `json:"note"`
+	Bonus *AnswerBonus `json:"bonus,omitempty"`
+}
+
+// AnswerBonus lists the repliers the answer bonus was minted to, and those whose mint is left for a retry.
+type AnswerBonus struct {
+	Amount     *big.Int         `json:"amount"`
+	Recipients []common.Address `json:"recipients"`
+	Pending    []common.Address `json:"pending,omitempty"`
+}
+
+type GetAnsweredNotesRequest struct {
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit" validate:"min=1,max=100" default:"20"`
+}
+
+// AnswerNote lets the author mark a note as answered, answering again only updates the testimony.
+func (h *Hub) AnswerNote(c echo.Context) error {
+	var request AnswerNoteRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	if request.Testimony != "" {
+		testimony, err := sanitizeNote(h.config.Note, request.Testimony)
+		if err != nil {
+			return errorx.ValidationFailedError(c, fmt.Errorf("testimony: %w", err))
+		}
+
+		request.Testimony = testimony
+	}
+
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	ctx := c.Request().Context()
+
+	note, err := h.authorNote(ctx, address, request.ID)
+	if err != nil {
+		return authorNoteError(c, request.ID, err)
+	}
+
+	first, err := h.databaseClient.AnswerNote(ctx, request.ID, request.Testimony)
+	if err != nil {
+		zap.L().Error("failed to answer note", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if first {
+		if err := h.notifyAnswered(ctx, note); err != nil {
+			zap.L().Error("failed to notify answered note", zap.String("id", request.ID), zap.Error(err))
+
+			return errorx.InternalError(c)
+		}
+	}
+
+	bonus, err := h.payAnswerBonus(ctx, note)
+	if err != nil {
+		zap.L().Error("failed to pay answer bonus", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	answered, err := h.databaseClient.FindNote(ctx, request.ID, &note.Address)
+	if err != nil {
+		zap.L().Error("failed to find note", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	zap.L().Info("answered note", zap.String("id", request.ID), zap.Bool("first", first), zap.Any("bonus", bonus))
+
+	return c.JSON(http.StatusOK, Response{
+		Data: AnswerNoteResponse{
+			Note:  h.newNoteView(answered, &note.Address, nil),
+			Bonus: bonus,
+		},
+	})
+}
+
+// GetAnsweredNotes is the public feed of answered prayers, most recently answered first.
+func (h *Hub) GetAnsweredNotes(c echo.Context) error {
+	var request GetAnsweredNotesRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := defaults.Set(&request); err != nil {
+		zap.L().Error("set default values for request", zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	// the feed is ordered by answer time rather than note id, so the cursor is an offset
+	offset, err := parseCursor(request.Cursor)
+	if err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("cursor: %w", err))
+	}
+
+	query := schema.AnsweredNoteQuery{
+		Limit: request.Limit,
+	}
+
+	if offset != nil {
+		query.Offset = int(*offset)
+	}
+
+	notes, err := h.databaseClient.FindAnsweredNotes(c.Request().Context(), query)
+	if err != nil {
+		zap.L().Error("failed to find answered notes", zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	authors := make([]common.Address, 0, len(notes))
+	for _, note := range notes {
+		authors = append(authors, note.Address)
+	}
+
+	names, err := h.displayNames(c.Request().Context(), authors...)
+	if err != nil {
+		zap.L().Error("failed to find display names", zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	views := make([]NoteView, 0, len(notes))
+	for _, note := range notes {
+		views = append(views, h.newNoteView(note, nil, names))
+	}
+
+	var nextCursor string
+	if len(notes) == request.Limit {
+		nextCursor = strconv.Itoa(query.Offset + len(notes))
+	}
+
+	return c.JSON(http.StatusOK, Response{
+		Data:   views,
+		Cursor: nextCursor,
+	})
+}
+
+// notifyAnswered tells everyone who replied to or reacted on the note that it was answered.
+func (h *Hub) notifyAnswered(ctx context.Context, note *schema.Note) error {
+	repliers, err := h.databaseClient.FindRepliers(ctx, note.MessageID)
+	if err != nil {
+		return fmt.Errorf("find repliers: %w", err)
+	}
+
+	reactors, err := h.databaseClient.FindReactors(ctx, note.MessageID)
+	if err != nil {
+		return fmt.Errorf("find reactors: %w", err)
+	}
+
+	// an anonymous author stays anonymous in the inbox of the readers
+	actor := note.Address
+	if note.Anonymous {
+		actor = zeroAddress
+	}
+
+	var notified []common.Address
+
+	for _, address := range append(repliers, reactors...) {
+		if address == note.Address || slices.Contains(notified, address) {
+			continue
+		}
+
+		if err := h.notify(ctx, &schema.InboxItem{
+			Address: address,
+			Type:    schema.InboxTypeAnswered,
+			NoteID:  note.MessageID,
+			Actor:   actor,
+		}); err != nil {
+			return err
+		}
+
+		notified = append(notified, address)
+	}
+
+	return nil
+}
+
+// payAnswerBonus mints the configured bonus to the earliest verified repliers, at most once per note.
+// Each bonus counts against the daily answer quota of the replier, a mint that fails is deferred to the mint retrier.
+// It returns nil when there is nothing to pay.
+func (h *Hub) payAnswerBonus(ctx context.Context, note *schema.Note) (*AnswerBonus, error) {
+	if h.config.Answer.Bonus == 0 {
+		return nil, nil
+	}
+
+	claimed, err := h.databaseClient.ClaimAnswerBonus(ctx, note.MessageID)
+	if err != nil {
+		return nil, fmt.Errorf("claim answer bonus: %w", err)
+	}
+
+	if !claimed {
+		return nil, nil
+	}
+
+	repliers, err := h.databaseClient.FindVerifiedRepliers(ctx, note.MessageID)
+	if err != nil {
+		return nil, fmt.Errorf("find verified repliers: %w", err)
+	}
+
+	bonus := &AnswerBonus{
+		Amount:     wholeTokens(h.config.Answer.Bonus),
+		Recipients: []common.Address{},
+	}
+
+	for _, replier := range repliers {
+		if len(bonus.Recipients)+len(bonus.Pending) == h.config.Answer.MaxBonusRecipients {
+			break
+		}
+
+		if replier == note.Address {
+			continue
+		}
+
+		withinQuota, err := h.consumeQuota(ctx, quotaScopeAnswer, replier, h.config.Answer.DailyQuota)
+		if err != nil {
+			zap.L().Error("failed to consume answer quota", zap.String("id", note.MessageID), zap.String("to", replier.Hex()), zap.Error(err))
+
+			continue
+		}
+
+		if !withinQuota {
+			continue
+		}
+
+		txHash, minted, err := h.mintOrDefer(ctx, replier, bonus.Amount, "answer:"+note.MessageID)
+		if err != nil {
+			zap.L().Error("failed to defer answer bonus", zap.String("id", note.MessageID), zap.String("to", replier.Hex()), zap.Error(err))
+
+			continue
+		}
+
+		if !minted {
+			bonus.Pending = append(bonus.Pending, replier)
+
+			continue
+		}
+
+		zap.L().Info("minted answer bonus", zap.String("id", note.MessageID), zap.String("to", replier.Hex()), zap.String("tx_hash", txHash.Hex()))
+
+		bonus.Recipients = append(bonus.Recipients, replier)
+	}
+
+	return bonus, nil
+}
@@ -0,0 +1,187 @@
+package hub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/internal/langdetect"
+	"github.com/brucexc/pray-to-earn/internal/service/hub/model/errorx"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/creasty/defaults"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	messagesExpiry = "messages_expiry"
+	archiveGrace   = 24 * time.Hour
+)
+
+type GetArchivedNotesRequest struct {
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit" validate:"min=1,max=100" default:"20"`
+}
+
+// RunArchiver periodically moves expired notes out of the random pool until the context is canceled.
+func (h *Hub) RunArchiver(ctx context.Context) {
+	ticker := time.NewTicker(h.config.Expiry.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				archived, err := h.archiveExpiredMessages(ctx)
+				if err != nil {
+					zap.L().Error("failed to archive expired notes", zap.Error(err))
+
+					break
+				}
+
+				if archived > 0 {
+					zap.L().Info("archived expired notes", zap.Int("count", archived))
+				}
+
+				// keep going while there are full batches left
+				if int64(archived) < h.config.Expiry.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// archiveExpiredMessages archives one batch of expired notes and returns how many were archived.
+func (h *Hub) archiveExpiredMessages(ctx context.Context) (int, error) {
+	messageIDs, err := h.redisClient.ZRangeByScore(ctx, messagesExpiry, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(time.Now().Unix(), 10),
+		Count: h.config.Expiry.BatchSize,
+	}).Result()
+	if err != nil {
+		return 0, fmt.Errorf("find expired notes: %w", err)
+	}
+
+	if len(messageIDs) == 0 {
+		return 0, nil
+	}
+
+	// the note store is the cold storage, mark the notes there before dropping them from redis
+	if err := h.databaseClient.ArchiveNotes(ctx, messageIDs); err != nil {
+		return 0, fmt.Errorf("archive notes: %w", err)
+	}
+
+	if err := h.removeFromPools(ctx, messageIDs...); err != nil {
+		return 0, fmt.Errorf("remove expired notes: %w", err)
+	}
+
+	return len(messageIDs), nil
+}
+
+// removeFromPools drops notes from every random selection pool and deletes their redis copies.
+func (h *Hub) removeFromPools(ctx context.Context, messageIDs ...string) error {
+	members := make([]interface{}, 0, len(messageIDs))
+	keys := make([]string, 0, len(messageIDs)*2)
+
+	for _, messageID := range messageIDs {
+		members = append(members, messageID)
+		keys = append(keys, fmt.Sprintf("message:%s", messageID), messageStatsKey(messageID))
+	}
+
+	circles, err := h.redisClient.HMGet(ctx, messagesCircle, messageIDs...).Result()
+	if err != nil {
+		return fmt.Errorf("find circles: %w", err)
+	}
+
+	pipeline := h.redisClient.TxPipeline()
+	pipeline.SRem(ctx, messagesSet, members...)
+
+	for i, circle := range circles {
+		if circle, ok := circle.(string); ok {
+			pipeline.SRem(ctx, circlePoolKey(circle), messageIDs[i])
+		}
+	}
+
+	pipeline.HDel(ctx, messagesCircle, messageIDs...)
+
+	for _, category := range h.config.Category.Categories {
+		pipeline.SRem(ctx, categoryPoolKey(category), members...)
+
+		// cached intersections would otherwise keep serving the notes until they expire
+		for _, language := range langdetect.Languages() {
+			pipeline.SRem(ctx, intersectionPoolKey(category, language), members...)
+		}
+	}
+
+	for _, language := range langdetect.Languages() {
+		pipeline.SRem(ctx, languagePoolKey(language), members...)
+	}
+
+	pipeline.ZRem(ctx, messagesExpiry, members...)
+	pipeline.Del(ctx, keys...)
+
+	_, err = pipeline.Exec(ctx)
+
+	return err
+}
+
+func (h *Hub) GetArchivedNotes(c echo.Context) error {
+	var request GetArchivedNotesRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := defaults.Set(&request); err != nil {
+		zap.L().Error("set default values for request", zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	cursor, err := parseCursor(request.Cursor)
+	if err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("cursor: %w", err))
+	}
+
+	notes, err := h.databaseClient.FindNotes(c.Request().Context(), schema.NoteQuery{
+		Address:  &address,
+		Archived: true,
+		Cursor:   cursor,
+		Limit:    request.Limit,
+	})
+	if err != nil {
+		zap.L().Error("failed to find archived notes", zap.String("author", address.Hex()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	views := make([]NoteView, 0, len(notes))
+	for _, note := range notes {
+		views = append(views, h.newNoteView(note, &address, nil))
+	}
+
+	var nextCursor string
+	if len(notes) == request.Limit {
+		nextCursor = strconv.FormatUint(notes[len(notes)-1].ID, 10)
+	}
+
+	return c.JSON(http.StatusOK, Response{
+		Data:   views,
+		Cursor: nextCursor,
+	})
+}
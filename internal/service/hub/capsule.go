@@ -0,0 +1,146 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/internal/database"
+	"github.com/brucexc/pray-to-earn/internal/service/hub/model/errorx"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/creasty/defaults"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// messagesSealed holds the time capsules waiting to be published, scored by their reveal time.
+const messagesSealed = "messages_sealed"
+
+type GetCapsulesRequest struct {
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit" validate:"min=1,max=100" default:"20"`
+}
+
+// RunUnsealer periodically publishes revealed time capsules until the context is canceled.
+func (h *Hub) RunUnsealer(ctx context.Context) {
+	ticker := time.NewTicker(h.config.Capsule.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				unsealed, err := h.unsealMessages(ctx)
+				if err != nil {
+					zap.L().Error("failed to unseal time capsules", zap.Error(err))
+
+					break
+				}
+
+				if unsealed > 0 {
+					zap.L().Info("unsealed time capsules", zap.Int("count", unsealed))
+				}
+
+				// keep going while there are full batches left
+				if int64(unsealed) < h.config.Capsule.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// unsealMessages publishes one batch of revealed time capsules and returns how many were handled.
+func (h *Hub) unsealMessages(ctx context.Context) (int, error) {
+	messageIDs, err := h.redisClient.ZRangeByScore(ctx, messagesSealed, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(time.Now().Unix(), 10),
+		Count: h.config.Capsule.BatchSize,
+	}).Result()
+	if err != nil {
+		return 0, fmt.Errorf("find revealed capsules: %w", err)
+	}
+
+	for _, messageID := range messageIDs {
+		// the capsule is looked up regardless of the reveal time, so clock skew with the database cannot drop it
+		note, err := h.databaseClient.FindCapsule(ctx, messageID)
+		if err != nil && !errors.Is(err, database.ErrorRowNotFound) {
+			return 0, fmt.Errorf("find note %s: %w", messageID, err)
+		}
+
+		// capsules deleted in the meantime are simply dropped
+		if note != nil {
+			if _, err := h.publishMessage(ctx, note, true); err != nil {
+				return 0, fmt.Errorf("publish note %s: %w", messageID, err)
+			}
+		}
+
+		if err := h.redisClient.ZRem(ctx, messagesSealed, messageID).Err(); err != nil {
+			return 0, fmt.Errorf("remove sealed note %s: %w", messageID, err)
+		}
+	}
+
+	return len(messageIDs), nil
+}
+
+// GetCapsules lists the time capsules of the signer that are still sealed.
+func (h *Hub) GetCapsules(c echo.Context) error {
+	var request GetCapsulesRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := defaults.Set(&request); err != nil {
+		zap.L().Error("set default values for request", zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	cursor, err := parseCursor(request.Cursor)
+	if err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("cursor: %w", err))
+	}
+
+	notes, err := h.databaseClient.FindNotes(c.Request().Context(), schema.NoteQuery{
+		Address: &address,
+		Sealed:  true,
+		Cursor:  cursor,
+		Limit:   request.Limit,
+	})
+	if err != nil {
+		zap.L().Error("failed to find sealed notes", zap.String("author", address.Hex()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	views := make([]NoteView, 0, len(notes))
+	for _, note := range notes {
+		views = append(views, h.newNoteView(note, &address, nil))
+	}
+
+	var nextCursor string
+	if len(notes) == request.Limit {
+		nextCursor = strconv.FormatUint(notes[len(notes)-1].ID, 10)
+	}
+
+	return c.JSON(http.StatusOK, Response{
+		Data:   views,
+		Cursor: nextCursor,
+	})
+}
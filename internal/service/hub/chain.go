@@ -0,0 +1,285 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/internal/database"
+	"github.com/brucexc/pray-to-earn/internal/langdetect"
+	"github.com/brucexc/pray-to-earn/internal/service/hub/model/errorx"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type StartChainRequest struct {
+	Line string `json:"line" validate:"required"`
+	// Length is how many contributors, the starter included, complete the chain.
+	Length int `json:"length" validate:"required"`
+}
+
+type GetChainRequest struct {
+	ID string `param:"id" validate:"required"`
+}
+
+type AppendChainRequest struct {
+	ID   string `param:"id" validate:"required"`
+	Line string `json:"line" validate:"required"`
+}
+
+type AppendChainResponse struct {
+	Chain  *schema.Chain `json:"chain"`
+	Reward *ChainReward  `json:"reward,omitempty"`
+}
+
+// ChainReward lists the contributors the completion reward was minted to, and those whose mint is left for a retry.
+type ChainReward struct {
+	Amount     *big.Int         `json:"amount"`
+	Recipients []common.Address `json:"recipients"`
+	Pending    []common.Address `json:"pending,omitempty"`
+}
+
+// StartChain opens a chain prayer note with the signer's line, it is handed to the next knockers one at a time
+// and joins the random pools once completed.
+func (h *Hub) StartChain(c echo.Context) error {
+	var request StartChainRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	if request.Length < h.config.Chain.MinLength || request.Length > h.config.Chain.MaxLength {
+		return errorx.ValidationFailedError(c, fmt.Errorf("length: must be between %d and %d", h.config.Chain.MinLength, h.config.Chain.MaxLength))
+	}
+
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	line, err := h.sanitizeChainLine(request.Line)
+	if err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("line: %w", err))
+	}
+
+	note := schema.Note{
+		MessageID:      uuid.New().String(),
+		Address:        address,
+		Note:           line,
+		Language:       langdetect.Detect(line),
+		SearchLanguage: h.config.Search.Language,
+	}
+
+	ctx := c.Request().Context()
+
+	chain, err := h.databaseClient.SaveChain(ctx, &note, request.Length)
+	if err != nil {
+		zap.L().Error("failed to save chain", zap.String("starter", address.Hex()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	// the starter is not handed their own chain once it joins the pools, as with any note they wrote
+	if err := h.redisClient.SAdd(ctx, authoredKey(address), note.MessageID).Err(); err != nil {
+		zap.L().Error("failed to record authored chain", zap.String("id", chain.ID), zap.Error(err))
+	}
+
+	zap.L().Info("started chain", zap.String("id", chain.ID), zap.String("starter", address.Hex()), zap.Int("length", chain.Length))
+
+	return c.JSON(http.StatusOK, Response{
+		Data: chain,
+	})
+}
+
+func (h *Hub) GetChain(c echo.Context) error {
+	var request GetChainRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	chain, err := h.databaseClient.FindChain(c.Request().Context(), request.ID)
+	if err != nil {
+		if errors.Is(err, database.ErrorRowNotFound) {
+			return errorx.NotFoundError(c, fmt.Errorf("chain %s not found", request.ID))
+		}
+
+		zap.L().Error("failed to find chain", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	return c.JSON(http.StatusOK, Response{
+		Data: chain,
+	})
+}
+
+// AppendChain adds the holder's line and passes the chain on, the last line completes it and rewards every contributor.
+func (h *Hub) AppendChain(c echo.Context) error {
+	var request AppendChainRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	line, err := h.sanitizeChainLine(request.Line)
+	if err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("line: %w", err))
+	}
+
+	ctx := c.Request().Context()
+
+	chain, err := h.databaseClient.AppendChain(ctx, request.ID, address, line, h.chainCutoff(), h.config.Expiry.Default)
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrorRowNotFound):
+			return errorx.NotFoundError(c, fmt.Errorf("chain %s not found", request.ID))
+		case errors.Is(err, database.ErrorChainNotHeld):
+			return errorx.ForbiddenError(c, fmt.Errorf("chain %s is not assigned to %s", request.ID, address.Hex()))
+		}
+
+		zap.L().Error("failed to append chain", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	zap.L().Info("appended chain", zap.String("id", chain.ID), zap.String("address", address.Hex()), zap.String("status", chain.Status))
+
+	var reward *ChainReward
+	if chain.Status == schema.ChainStatusCompleted {
+		if err := h.publishChain(ctx, chain); err != nil {
+			zap.L().Error("failed to publish chain, queued for the unsealer", zap.String("id", chain.ID), zap.Error(err))
+
+			// the chain is already completed in the database, so the unsealer publishes it on its next pass
+			if err := h.queueChain(context.WithoutCancel(ctx), chain); err != nil {
+				zap.L().Error("failed to queue chain", zap.String("id", chain.ID), zap.Error(err))
+
+				return errorx.InternalError(c)
+			}
+		}
+
+		reward = h.payChainReward(ctx, chain)
+	}
+
+	return c.JSON(http.StatusOK, Response{
+		Data: AppendChainResponse{
+			Chain:  chain,
+			Reward: reward,
+		},
+	})
+}
+
+// assignChain returns the chain a knocker should contribute to next, or nil when none is waiting.
+func (h *Hub) assignChain(ctx context.Context, address common.Address) (*schema.Chain, error) {
+	chain, err := h.databaseClient.AssignChain(ctx, address, h.chainCutoff())
+	if err != nil {
+		if errors.Is(err, database.ErrorRowNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return chain, nil
+}
+
+// chainCutoff is the time before which an assignment has lapsed and the chain may go to someone else.
+func (h *Hub) chainCutoff() time.Time {
+	return time.Now().Add(-h.config.Chain.HoldTimeout)
+}
+
+func (h *Hub) sanitizeChainLine(line string) (string, error) {
+	rules := *h.config.Note
+	rules.MaxRunes = h.config.Chain.MaxLineRunes
+
+	return sanitizeNote(&rules, line)
+}
+
+// publishChain adds a completed chain prayer to the random pools like any other note.
+func (h *Hub) publishChain(ctx context.Context, chain *schema.Chain) error {
+	note, err := h.databaseClient.FindNote(ctx, chain.ID, nil)
+	if err != nil {
+		return fmt.Errorf("find chain note: %w", err)
+	}
+
+	_, err = h.publishMessage(ctx, note, true)
+
+	return err
+}
+
+// queueChain hands a completed chain to the unsealer, which publishes any revealed note it finds in messages_sealed.
+func (h *Hub) queueChain(ctx context.Context, chain *schema.Chain) error {
+	return h.redisClient.ZAdd(ctx, messagesSealed, redis.Z{
+		Score:  float64(time.Now().Unix()),
+		Member: chain.ID,
+	}).Err()
+}
+
+// payChainReward mints the completion reward to every contributor within their daily chain quota.
+// Contributors whose mint fails are listed as pending, the mint retrier pays them later.
+func (h *Hub) payChainReward(ctx context.Context, chain *schema.Chain) *ChainReward {
+	if h.config.Chain.Reward == 0 {
+		return nil
+	}
+
+	reward := &ChainReward{
+		Amount:     wholeTokens(h.config.Chain.Reward),
+		Recipients: []common.Address{},
+	}
+
+	for _, link := range chain.Links {
+		withinQuota, err := h.consumeQuota(ctx, quotaScopeChain, link.Address, h.config.Chain.DailyQuota)
+		if err != nil {
+			zap.L().Error("failed to consume chain quota", zap.String("id", chain.ID), zap.String("to", link.Address.Hex()), zap.Error(err))
+
+			continue
+		}
+
+		if !withinQuota {
+			continue
+		}
+
+		txHash, minted, err := h.mintOrDefer(ctx, link.Address, reward.Amount, "chain:"+chain.ID)
+		if err != nil {
+			zap.L().Error("failed to defer chain reward", zap.String("id", chain.ID), zap.String("to", link.Address.Hex()), zap.Error(err))
+
+			continue
+		}
+
+		if !minted {
+			reward.Pending = append(reward.Pending, link.Address)
+
+			continue
+		}
+
+		zap.L().Info("minted chain reward", zap.String("id", chain.ID), zap.String("to", link.Address.Hex()), zap.String("tx_hash", txHash.Hex()))
+
+		reward.Recipients = append(reward.Recipients, link.Address)
+	}
+
+	return reward
+}
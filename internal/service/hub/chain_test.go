@@ -0,0 +1,36 @@
+package hub
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestQueueChain(t *testing.T) {
+	t.Parallel()
+
+	hub, _ := newTestHub(t)
+
+	ctx := context.Background()
+
+	if err := hub.queueChain(ctx, &schema.Chain{ID: "chain"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the unsealer only reads capsules whose reveal time has passed
+	due, err := hub.redisClient.ZRangeByScore(ctx, messagesSealed, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(time.Now().Unix(), 10),
+	}).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(due) != 1 || due[0] != "chain" {
+		t.Errorf("got %v, want [chain]", due)
+	}
+}
@@ -0,0 +1,239 @@
+package hub
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/internal/service/hub/model/errorx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// ErrChallengeSpent is returned when a solved challenge was already used by an accepted knock.
+var ErrChallengeSpent = errors.New("already used")
+
+type GetChallengeRequest struct {
+	Address common.Address `query:"address" validate:"required"`
+}
+
+// Challenge is a hashcash puzzle bound to one address, the signature keeps clients from choosing their own difficulty.
+type Challenge struct {
+	Nonce      string `json:"nonce"`
+	Difficulty int    `json:"difficulty"`
+	ExpiresAt  int64  `json:"expires_at"`
+	Signature  string `json:"signature"`
+}
+
+// ChallengeSolution is a challenge handed back with a counter that makes
+// sha256("<nonce>:<address>:<solution>") start with at least difficulty zero bits.
+type ChallengeSolution struct {
+	Challenge
+	Solution uint64 `json:"solution"`
+}
+
+// GetChallenge issues a proof-of-work challenge for the next knock of an address.
+func (h *Hub) GetChallenge(c echo.Context) error {
+	var request GetChallengeRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	difficulty, err := h.challengeDifficulty(c.Request().Context(), c.RealIP(), request.Address)
+	if err != nil {
+		zap.L().Error("failed to compute challenge difficulty", zap.String("address", request.Address.Hex()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		zap.L().Error("failed to generate challenge nonce", zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	challenge := Challenge{
+		Nonce:      hex.EncodeToString(nonce),
+		Difficulty: difficulty,
+		ExpiresAt:  time.Now().Add(h.config.Challenge.TTL).Unix(),
+	}
+	challenge.Signature = h.signChallenge(request.Address, challenge)
+
+	return c.JSON(http.StatusOK, Response{
+		Data: challenge,
+	})
+}
+
+// verifyChallenge checks the solution of a knock, failures count against the risk of the knocker.
+// The challenge is left unspent, spendChallenge spends it once the knock is accepted.
+func (h *Hub) verifyChallenge(ctx context.Context, ip string, address common.Address, solution *ChallengeSolution) error {
+	err := h.checkChallenge(address, solution)
+	if err != nil {
+		if riskErr := h.addChallengeRisk(ctx, ip, address, h.config.Challenge.FailurePenalty); riskErr != nil {
+			zap.L().Error("failed to record challenge failure", zap.String("address", address.Hex()), zap.Error(riskErr))
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// spendChallenge uses up a verified challenge for an accepted knock and counts the knock, a challenge is good for one knock only.
+// It returns ErrChallengeSpent when another knock already used it.
+func (h *Hub) spendChallenge(ctx context.Context, ip string, address common.Address, solution *ChallengeSolution) error {
+	spent, err := h.redisClient.SetNX(ctx, challengeSpentKey(solution.Nonce), 1, time.Until(time.Unix(solution.ExpiresAt, 0))+time.Second).Result()
+	if err != nil {
+		return fmt.Errorf("spend challenge: %w", err)
+	}
+
+	if !spent {
+		return ErrChallengeSpent
+	}
+
+	pipeline := h.redisClient.TxPipeline()
+	pipeline.Incr(ctx, challengeRateKey(time.Now()))
+	pipeline.Expire(ctx, challengeRateKey(time.Now()), 2*time.Minute)
+
+	if _, err := pipeline.Exec(ctx); err != nil {
+		return fmt.Errorf("count knock: %w", err)
+	}
+
+	return h.addChallengeRisk(ctx, ip, address, 1)
+}
+
+// checkChallenge checks that the solution answers a challenge issued to the address that has not expired yet.
+func (h *Hub) checkChallenge(address common.Address, solution *ChallengeSolution) error {
+	if solution == nil {
+		return fmt.Errorf("required, fetch one from /pray/challenge")
+	}
+
+	expected := h.signChallenge(address, solution.Challenge)
+	if !hmac.Equal([]byte(expected), []byte(solution.Signature)) {
+		return fmt.Errorf("invalid signature")
+	}
+
+	if time.Now().Unix() > solution.ExpiresAt {
+		return fmt.Errorf("expired")
+	}
+
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%d", solution.Nonce, address.Hex(), solution.Solution)))
+	if leadingZeroBits(hash[:]) < solution.Difficulty {
+		return fmt.Errorf("solution does not meet difficulty %d", solution.Difficulty)
+	}
+
+	return nil
+}
+
+// challengeDifficulty raises the base difficulty with the global knock rate and the risk of the IP and address.
+// An address that never wrote a note starts at the new address risk, so rotating addresses does not reset the difficulty.
+func (h *Hub) challengeDifficulty(ctx context.Context, ip string, address common.Address) (int, error) {
+	policy := h.config.Challenge
+	now := time.Now()
+
+	pipeline := h.redisClient.Pipeline()
+	current := pipeline.Get(ctx, challengeRateKey(now))
+	previous := pipeline.Get(ctx, challengeRateKey(now.Add(-time.Minute)))
+	ipRisk := pipeline.Get(ctx, challengeRiskKey("ip", ip))
+	addressRisk := pipeline.Get(ctx, challengeRiskKey("address", address.Hex()))
+	authored := pipeline.Exists(ctx, authoredKey(address))
+
+	if _, err := pipeline.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+
+	// the current minute has only just started, so the previous one counts as well
+	rate := max(counterValue(current), counterValue(previous))
+	risk := counterValue(ipRisk) + counterValue(addressRisk)
+
+	if authored.Val() == 0 {
+		risk += policy.NewAddressRisk
+	}
+
+	difficulty := policy.BaseDifficulty + int(rate/policy.KnocksPerBit) + int(risk/policy.RiskPerBit)
+
+	return min(difficulty, policy.MaxDifficulty), nil
+}
+
+func (h *Hub) addChallengeRisk(ctx context.Context, ip string, address common.Address, points int64) error {
+	if points == 0 {
+		return nil
+	}
+
+	pipeline := h.redisClient.TxPipeline()
+
+	for _, key := range []string{challengeRiskKey("ip", ip), challengeRiskKey("address", address.Hex())} {
+		pipeline.IncrBy(ctx, key, points)
+		pipeline.Expire(ctx, key, h.config.Challenge.RiskWindow)
+	}
+
+	if _, err := pipeline.Exec(ctx); err != nil {
+		return fmt.Errorf("add challenge risk: %w", err)
+	}
+
+	return nil
+}
+
+func (h *Hub) signChallenge(address common.Address, challenge Challenge) string {
+	secret := h.config.Challenge.Secret
+	if secret == "" {
+		secret = h.config.AdminKey
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%s:%s:%d:%d", address.Hex(), challenge.Nonce, challenge.Difficulty, challenge.ExpiresAt)))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func challengeRateKey(at time.Time) string {
+	return fmt.Sprintf("challenge:rate:%d", at.Unix()/60)
+}
+
+func challengeSpentKey(nonce string) string {
+	return fmt.Sprintf("challenge:spent:%s", nonce)
+}
+
+func challengeRiskKey(kind, subject string) string {
+	return fmt.Sprintf("challenge:risk:%s:%s", kind, subject)
+}
+
+// counterValue reads a redis counter, a missing key counts as zero.
+func counterValue(cmd *redis.StringCmd) int64 {
+	value, err := strconv.ParseInt(cmd.Val(), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return value
+}
+
+func leadingZeroBits(hash []byte) int {
+	var count int
+
+	for _, b := range hash {
+		if b != 0 {
+			return count + bits.LeadingZeros8(b)
+		}
+
+		count += 8
+	}
+
+	return count
+}
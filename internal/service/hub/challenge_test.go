@@ -0,0 +1,205 @@
+package hub
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestLeadingZeroBits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		hash []byte
+		want int
+	}{
+		{name: "high bit set", hash: []byte{0x80, 0x00}, want: 0},
+		{name: "low bit set", hash: []byte{0x01, 0xff}, want: 7},
+		{name: "first byte zero", hash: []byte{0x00, 0x40}, want: 9},
+		{name: "all zero", hash: []byte{0x00, 0x00}, want: 16},
+		{name: "empty", hash: []byte{}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := leadingZeroBits(tt.hash); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// solveChallenge brute forces a solution, tests keep the difficulty low.
+func solveChallenge(t *testing.T, address common.Address, challenge Challenge) ChallengeSolution {
+	t.Helper()
+
+	for solution := uint64(0); ; solution++ {
+		hash := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%d", challenge.Nonce, address.Hex(), solution)))
+		if leadingZeroBits(hash[:]) >= challenge.Difficulty {
+			return ChallengeSolution{Challenge: challenge, Solution: solution}
+		}
+	}
+}
+
+func TestCheckChallenge(t *testing.T) {
+	t.Parallel()
+
+	hub, _ := newTestHub(t)
+	hub.config.Challenge.Secret = "secret"
+
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	other := common.HexToAddress("0x0000000000000000000000000000000000000002")
+
+	issue := func(difficulty int, expiresAt time.Time) Challenge {
+		challenge := Challenge{Nonce: "00112233445566778899aabbccddeeff", Difficulty: difficulty, ExpiresAt: expiresAt.Unix()}
+		challenge.Signature = hub.signChallenge(address, challenge)
+
+		return challenge
+	}
+
+	valid := solveChallenge(t, address, issue(8, time.Now().Add(time.Minute)))
+
+	tests := []struct {
+		name     string
+		address  common.Address
+		solution func() *ChallengeSolution
+		wantErr  bool
+	}{
+		{
+			name:     "valid",
+			address:  address,
+			solution: func() *ChallengeSolution { return &valid },
+		},
+		{
+			name:     "missing",
+			address:  address,
+			solution: func() *ChallengeSolution { return nil },
+			wantErr:  true,
+		},
+		{
+			name:     "issued to another address",
+			address:  other,
+			solution: func() *ChallengeSolution { return &valid },
+			wantErr:  true,
+		},
+		{
+			name:    "lowered difficulty",
+			address: address,
+			solution: func() *ChallengeSolution {
+				solution := valid
+				solution.Difficulty = 0
+
+				return &solution
+			},
+			wantErr: true,
+		},
+		{
+			name:    "expired",
+			address: address,
+			solution: func() *ChallengeSolution {
+				solution := solveChallenge(t, address, issue(4, time.Now().Add(-time.Second)))
+
+				return &solution
+			},
+			wantErr: true,
+		},
+		{
+			name:    "does not meet difficulty",
+			address: address,
+			solution: func() *ChallengeSolution {
+				challenge := issue(32, time.Now().Add(time.Minute))
+
+				for solution := uint64(0); ; solution++ {
+					hash := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%d", challenge.Nonce, address.Hex(), solution)))
+					if leadingZeroBits(hash[:]) < challenge.Difficulty {
+						return &ChallengeSolution{Challenge: challenge, Solution: solution}
+					}
+				}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := hub.checkChallenge(tt.address, tt.solution())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error: got %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSpendChallenge(t *testing.T) {
+	t.Parallel()
+
+	hub, _ := newTestHub(t)
+
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	solution := &ChallengeSolution{Challenge: Challenge{Nonce: "nonce", ExpiresAt: time.Now().Add(time.Minute).Unix()}}
+
+	ctx := context.Background()
+
+	if err := hub.spendChallenge(ctx, "192.0.2.1", address, solution); err != nil {
+		t.Fatalf("first spend: %v", err)
+	}
+
+	if err := hub.spendChallenge(ctx, "192.0.2.1", address, solution); !errors.Is(err, ErrChallengeSpent) {
+		t.Errorf("second spend: got %v, want %v", err, ErrChallengeSpent)
+	}
+}
+
+func TestChallengeDifficulty(t *testing.T) {
+	t.Parallel()
+
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	tests := []struct {
+		name     string
+		authored bool
+		risk     int64
+		want     int
+	}{
+		{name: "new address starts at the baseline risk", want: 16 + 2},
+		{name: "address that wrote a note", authored: true, want: 16},
+		{name: "risky address", authored: true, risk: 30, want: 16 + 3},
+		{name: "capped", risk: 1000, want: 24},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hub, server := newTestHub(t)
+
+			if tt.authored {
+				if _, err := server.SAdd(authoredKey(address), "note"); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if tt.risk > 0 {
+				_ = server.Set(challengeRiskKey("address", address.Hex()), fmt.Sprint(tt.risk))
+			}
+
+			got, err := hub.challengeDifficulty(context.Background(), "192.0.2.1", address)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
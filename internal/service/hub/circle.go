@@ -0,0 +1,259 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/brucexc/pray-to-earn/internal/database"
+	"github.com/brucexc/pray-to-earn/internal/service/hub/model/errorx"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type CreateCircleRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type InviteCircleRequest struct {
+	ID        string           `param:"id" validate:"required"`
+	Addresses []common.Address `json:"addresses" validate:"required,min=1,max=100"`
+}
+
+type InviteCircleResponse struct {
+	Invited int64 `json:"invited"`
+}
+
+type JoinCircleRequest struct {
+	ID string `param:"id" validate:"required"`
+}
+
+var (
+	ErrCircleSignature = errors.New("circle notes need a signed request")
+	ErrNotCircleReader = errors.New("not a joined member of the circle")
+)
+
+func (h *Hub) CreateCircle(c echo.Context) error {
+	var request CreateCircleRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	rules := *h.config.Note
+	rules.MaxRunes = h.config.Circle.MaxNameRunes
+
+	name, err := sanitizeNote(&rules, request.Name)
+	if err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("name: %w", err))
+	}
+
+	circle := schema.Circle{
+		ID:    uuid.New().String(),
+		Name:  name,
+		Owner: address,
+	}
+
+	if err := h.databaseClient.SaveCircle(c.Request().Context(), &circle); err != nil {
+		zap.L().Error("failed to save circle", zap.String("owner", address.Hex()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	zap.L().Info("created circle", zap.String("id", circle.ID), zap.String("owner", address.Hex()))
+
+	return c.JSON(http.StatusOK, Response{
+		Data: circle,
+	})
+}
+
+// GetCircles lists the circles the signer has joined or been invited to.
+func (h *Hub) GetCircles(c echo.Context) error {
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	circles, err := h.databaseClient.FindCircles(c.Request().Context(), address)
+	if err != nil {
+		zap.L().Error("failed to find circles", zap.String("address", address.Hex()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	return c.JSON(http.StatusOK, Response{
+		Data: circles,
+	})
+}
+
+// InviteCircle lets the circle owner invite addresses, they become members once they join.
+func (h *Hub) InviteCircle(c echo.Context) error {
+	var request InviteCircleRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	ctx := c.Request().Context()
+
+	circle, err := h.databaseClient.FindCircle(ctx, request.ID)
+	if err != nil {
+		if errors.Is(err, database.ErrorRowNotFound) {
+			return errorx.NotFoundError(c, fmt.Errorf("circle %s not found", request.ID))
+		}
+
+		zap.L().Error("failed to find circle", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if circle.Owner != address {
+		return errorx.ForbiddenError(c, fmt.Errorf("only the circle owner can invite"))
+	}
+
+	count, err := h.databaseClient.CountCircleMembers(ctx, request.ID)
+	if err != nil {
+		zap.L().Error("failed to count circle members", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	// addresses already in the circle are counted too, so the cap may be a little conservative
+	if count+int64(len(request.Addresses)) > h.config.Circle.MaxMembers {
+		return errorx.ValidationFailedError(c, fmt.Errorf("addresses: a circle has at most %d members", h.config.Circle.MaxMembers))
+	}
+
+	invited, err := h.databaseClient.InviteCircleMembers(ctx, request.ID, address, request.Addresses)
+	if err != nil {
+		zap.L().Error("failed to invite circle members", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	zap.L().Info("invited circle members", zap.String("id", request.ID), zap.Int64("invited", invited))
+
+	return c.JSON(http.StatusOK, Response{
+		Data: InviteCircleResponse{
+			Invited: invited,
+		},
+	})
+}
+
+// JoinCircle accepts an invitation, the signature is the invitee's acceptance.
+func (h *Hub) JoinCircle(c echo.Context) error {
+	var request JoinCircleRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	if err := h.databaseClient.JoinCircle(c.Request().Context(), request.ID, address); err != nil {
+		if errors.Is(err, database.ErrorRowNotFound) {
+			return errorx.NotFoundError(c, fmt.Errorf("no pending invitation to circle %s", request.ID))
+		}
+
+		zap.L().Error("failed to join circle", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	zap.L().Info("joined circle", zap.String("id", request.ID), zap.String("address", address.Hex()))
+
+	return c.JSON(http.StatusOK, Response{
+		Data: "ok",
+	})
+}
+
+func (h *Hub) isCircleMember(ctx context.Context, circleID string, address common.Address) (bool, error) {
+	member, err := h.databaseClient.FindCircleMember(ctx, circleID, address)
+	if err != nil {
+		if errors.Is(err, database.ErrorRowNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return member.Status == schema.CircleMemberStatusJoined, nil
+}
+
+// canRead reports whether the viewer may see the note, circle notes are only visible to joined members.
+func (h *Hub) canRead(ctx context.Context, note *schema.Note, viewer *common.Address) (bool, error) {
+	if note.Circle == "" {
+		return true, nil
+	}
+
+	if viewer == nil {
+		return false, nil
+	}
+
+	return h.isCircleMember(ctx, note.Circle, *viewer)
+}
+
+// checkCircleReader makes sure a request acting for address on a circle note is signed by that address as a joined member.
+func (h *Hub) checkCircleReader(c echo.Context, note *schema.Note, address common.Address) error {
+	if note.Circle == "" {
+		return nil
+	}
+
+	viewer, err := signer(c)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCircleSignature, err)
+	}
+
+	readable, err := h.canRead(c.Request().Context(), note, &viewer)
+	if err != nil {
+		return fmt.Errorf("check circle membership: %w", err)
+	}
+
+	if !readable || viewer != address {
+		return ErrNotCircleReader
+	}
+
+	return nil
+}
+
+// circleReaderError maps a checkCircleReader failure to its response, outsiders are told the note does not exist.
+func circleReaderError(c echo.Context, messageID string, err error) error {
+	switch {
+	case errors.Is(err, ErrCircleSignature):
+		return errorx.UnauthorizedError(c, err)
+	case errors.Is(err, ErrNotCircleReader):
+		return errorx.NotFoundError(c, fmt.Errorf("note %s not found", messageID))
+	}
+
+	zap.L().Error("failed to check circle reader", zap.String("id", messageID), zap.Error(err))
+
+	return errorx.InternalError(c)
+}
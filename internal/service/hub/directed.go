@@ -0,0 +1,178 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/brucexc/pray-to-earn/internal/service/hub/model/errorx"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrGiftBudgetExceeded       = errors.New("daily gift budget exceeded")
+	ErrGiftGlobalBudgetExceeded = errors.New("daily gift budget of the server exceeded")
+)
+
+// DirectedGift is the token gift minted to the recipient of a directed note.
+type DirectedGift struct {
+	Recipient common.Address `json:"recipient"`
+	Amount    *big.Int       `json:"amount"`
+	TxHash    common.Hash    `json:"tx_hash"`
+	// Pending marks a gift whose mint failed and is left for the mint retrier.
+	Pending bool `json:"pending,omitempty"`
+}
+
+// claimGift counts a gift against the daily budgets of the sender and the server and spends the burn that funds it.
+// Nothing is held when it fails.
+func (h *Hub) claimGift(ctx context.Context, sender common.Address, txHash common.Hash, amount int64) error {
+	withinBudget, err := h.consumeQuotaBy(ctx, quotaScopeGift, sender, amount, h.config.Gift.DailyBudget)
+	if err != nil {
+		return fmt.Errorf("consume gift budget: %w", err)
+	}
+
+	if !withinBudget {
+		return ErrGiftBudgetExceeded
+	}
+
+	withinBudget, err = h.consumeQuotaBy(ctx, quotaScopeGiftGlobal, serverAdminAddress, amount, h.config.Gift.GlobalDailyBudget)
+	if err != nil || !withinBudget {
+		if refundErr := h.refundQuota(ctx, quotaScopeGift, sender, amount); refundErr != nil {
+			zap.L().Error("failed to refund gift budget", zap.String("address", sender.Hex()), zap.Error(refundErr))
+		}
+
+		if err != nil {
+			return fmt.Errorf("consume global gift budget: %w", err)
+		}
+
+		return ErrGiftGlobalBudgetExceeded
+	}
+
+	spent, err := h.databaseClient.SaveBurn(ctx, &schema.Burn{
+		TransactionHash: txHash,
+		Purpose:         schema.BurnPurposeGift,
+		Address:         sender,
+		Amount:          wholeTokens(amount),
+	})
+	if err != nil || !spent {
+		h.refundGift(ctx, sender, amount)
+
+		if err != nil {
+			return fmt.Errorf("save burn: %w", err)
+		}
+
+		return fmt.Errorf("%w: transaction %s was already used", ErrBadPayment, txHash.Hex())
+	}
+
+	return nil
+}
+
+// releaseGift undoes claimGift for a gift that will not be paid, the burn can then fund another gift.
+func (h *Hub) releaseGift(ctx context.Context, sender common.Address, txHash common.Hash, amount int64) {
+	h.refundGift(ctx, sender, amount)
+
+	if err := h.databaseClient.DeleteBurn(context.WithoutCancel(ctx), txHash); err != nil {
+		zap.L().Error("failed to release gift burn", zap.String("tx_hash", txHash.Hex()), zap.Error(err))
+	}
+}
+
+func (h *Hub) refundGift(ctx context.Context, sender common.Address, amount int64) {
+	if err := h.refundQuota(context.WithoutCancel(ctx), quotaScopeGift, sender, amount); err != nil {
+		zap.L().Error("failed to refund gift budget", zap.String("address", sender.Hex()), zap.Error(err))
+	}
+
+	if err := h.refundQuota(context.WithoutCancel(ctx), quotaScopeGiftGlobal, serverAdminAddress, amount); err != nil {
+		zap.L().Error("failed to refund global gift budget", zap.Error(err))
+	}
+}
+
+// giftError writes the response for an error returned by claimGift.
+func giftError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, ErrGiftBudgetExceeded), errors.Is(err, ErrGiftGlobalBudgetExceeded):
+		return errorx.TooManyRequestError(c, fmt.Errorf("gift: %w", err))
+	case errors.Is(err, ErrBadPayment):
+		return errorx.BadPaymentError(c, err)
+	default:
+		zap.L().Error("failed to claim gift", zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+}
+
+// directedKey holds the notes waiting to be offered to their recipient.
+func directedKey(address common.Address) string {
+	return fmt.Sprintf("notes:directed:%s", address.Hex())
+}
+
+// deliverDirected queues a published note for its recipient and tells them about it in their inbox.
+func (h *Hub) deliverDirected(ctx context.Context, note *schema.Note) error {
+	recipient := *note.Recipient
+
+	pipeline := h.redisClient.TxPipeline()
+	pipeline.SAdd(ctx, directedKey(recipient), note.MessageID)
+	pipeline.Expire(ctx, directedKey(recipient), h.config.Expiry.Max)
+
+	if _, err := pipeline.Exec(ctx); err != nil {
+		return fmt.Errorf("queue directed note: %w", err)
+	}
+
+	actor := note.Address
+	if note.Anonymous {
+		actor = zeroAddress
+	}
+
+	return h.notify(ctx, &schema.InboxItem{
+		Address: recipient,
+		Type:    schema.InboxTypeDirected,
+		NoteID:  note.MessageID,
+		Actor:   actor,
+	})
+}
+
+// pickDirectedMessageID takes the next note directed to the address that matches the category or circle of the filter,
+// skipping notes that expired or were already served. Notes outside the filter stay queued for a later pick.
+func (h *Hub) pickDirectedMessageID(ctx context.Context, address common.Address, filter MessageFilter) (string, error) {
+	var (
+		candidates []string
+		err        error
+	)
+
+	switch {
+	case filter.Circle != "":
+		candidates, err = h.redisClient.SInter(ctx, directedKey(address), circlePoolKey(filter.Circle)).Result()
+	case filter.Category != "":
+		candidates, err = h.redisClient.SInter(ctx, directedKey(address), categoryPoolKey(filter.Category)).Result()
+	default:
+		candidates, err = h.redisClient.SMembers(ctx, directedKey(address)).Result()
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("find directed notes: %w", err)
+	}
+
+	for _, messageID := range candidates {
+		pipeline := h.redisClient.Pipeline()
+		exists := pipeline.Exists(ctx, fmt.Sprintf("message:%s", messageID))
+		seen := pipeline.SIsMember(ctx, seenKey(address), messageID)
+
+		if _, err := pipeline.Exec(ctx); err != nil {
+			return "", fmt.Errorf("check directed note: %w", err)
+		}
+
+		// a candidate leaves the queue whether it is served now or can never be served
+		if err := h.redisClient.SRem(ctx, directedKey(address), messageID).Err(); err != nil {
+			return "", fmt.Errorf("dequeue directed note: %w", err)
+		}
+
+		if exists.Val() == 1 && !seen.Val() {
+			return messageID, nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,158 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestClaimGiftBudgets(t *testing.T) {
+	t.Parallel()
+
+	sender := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	tests := []struct {
+		name         string
+		senderUsed   int64
+		globalUsed   int64
+		amount       int64
+		globalBudget int64
+		wantErr      error
+		wantSender   int64
+		wantGlobal   int64
+	}{
+		{
+			name:         "sender budget exceeded",
+			senderUsed:   18,
+			amount:       3,
+			globalBudget: 1000,
+			wantErr:      ErrGiftBudgetExceeded,
+			wantSender:   18,
+		},
+		{
+			name:         "global budget exceeded refunds the sender",
+			senderUsed:   2,
+			globalUsed:   999,
+			amount:       2,
+			globalBudget: 1000,
+			wantErr:      ErrGiftGlobalBudgetExceeded,
+			wantSender:   2,
+			wantGlobal:   999,
+		},
+		{
+			name:         "global budget disabled",
+			amount:       1,
+			globalBudget: 0,
+			wantErr:      ErrGiftGlobalBudgetExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hub, server := newTestHub(t)
+			hub.config.Gift.GlobalDailyBudget = tt.globalBudget
+
+			ctx := context.Background()
+
+			if tt.senderUsed > 0 {
+				_ = server.Set(quotaKey(quotaScopeGift, sender), strconv.FormatInt(tt.senderUsed, 10))
+			}
+
+			if tt.globalUsed > 0 {
+				_ = server.Set(quotaKey(quotaScopeGiftGlobal, serverAdminAddress), strconv.FormatInt(tt.globalUsed, 10))
+			}
+
+			err := hub.claimGift(ctx, sender, common.Hash{}, tt.amount)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error: got %v, want %v", err, tt.wantErr)
+			}
+
+			for key, want := range map[string]int64{
+				quotaKey(quotaScopeGift, sender):                   tt.wantSender,
+				quotaKey(quotaScopeGiftGlobal, serverAdminAddress): tt.wantGlobal,
+				// the admin wallet keeps its own sender budget
+				quotaKey(quotaScopeGift, serverAdminAddress): 0,
+			} {
+				value, _ := server.Get(key)
+				if got, _ := strconv.ParseInt(value, 10, 64); got != want {
+					t.Errorf("%s: got %d, want %d", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPickDirectedMessageID(t *testing.T) {
+	t.Parallel()
+
+	recipient := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	tests := []struct {
+		name   string
+		filter MessageFilter
+		// want lists the acceptable picks, an empty list expects none
+		want []string
+		// wantQueued must stay queued, a filtered pick only dequeues what it looked at
+		wantQueued []string
+	}{
+		{
+			name:   "no filter",
+			filter: MessageFilter{},
+			want:   []string{"health", "family"},
+		},
+		{
+			name:       "category",
+			filter:     MessageFilter{Category: "health"},
+			want:       []string{"health"},
+			wantQueued: []string{"family", "gone", "seen"},
+		},
+		{
+			name:       "category without directed notes",
+			filter:     MessageFilter{Category: "work"},
+			wantQueued: []string{"health", "family", "gone", "seen"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hub, server := newTestHub(t)
+
+			server.SAdd(directedKey(recipient), "health", "family", "gone", "seen")
+			server.SAdd(categoryPoolKey("health"), "health")
+			server.SAdd(categoryPoolKey("family"), "family")
+			server.SAdd(seenKey(recipient), "seen")
+
+			for _, messageID := range []string{"health", "family", "seen"} {
+				_ = server.Set("message:"+messageID, "{}")
+			}
+
+			got, err := hub.pickDirectedMessageID(context.Background(), recipient, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if (len(tt.want) == 0 && got != "") || (len(tt.want) > 0 && !slices.Contains(tt.want, got)) {
+				t.Fatalf("got %q, want one of %v", got, tt.want)
+			}
+
+			queued, _ := server.Members(directedKey(recipient))
+			if slices.Contains(queued, got) {
+				t.Errorf("picked note %q is still queued", got)
+			}
+
+			for _, messageID := range tt.wantQueued {
+				if !slices.Contains(queued, messageID) {
+					t.Errorf("note %q left the queue, want it kept for a later pick", messageID)
+				}
+			}
+		})
+	}
+}
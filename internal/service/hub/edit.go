@@ -0,0 +1,212 @@
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/brucexc/pray-to-earn/internal/database"
+	"github.com/brucexc/pray-to-earn/internal/langdetect"
+	"github.com/brucexc/pray-to-earn/internal/service/hub/model/errorx"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type EditNoteRequest struct {
+	ID   string `param:"id" validate:"required"`
+	Note string `json:"note" validate:"required"`
+}
+
+type DeleteNoteRequest struct {
+	ID string `param:"id" validate:"required"`
+}
+
+// ErrNotAuthor is returned when a note is changed by someone other than its author.
+var ErrNotAuthor = errors.New("only the author can change this note")
+
+func (h *Hub) EditNote(c echo.Context) error {
+	var request EditNoteRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	text, err := sanitizeNote(h.config.Note, request.Note)
+	if err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("note: %w", err))
+	}
+
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	ctx := c.Request().Context()
+
+	note, err := h.authorNote(ctx, address, request.ID)
+	if err != nil {
+		return authorNoteError(c, request.ID, err)
+	}
+
+	// every contributor wrote a line of a chain prayer, so the starter cannot rewrite it alone
+	if note.Kind == schema.NoteKindChain {
+		return errorx.ForbiddenError(c, fmt.Errorf("chain prayer %s cannot be edited", request.ID))
+	}
+
+	language := langdetect.Detect(text)
+
+	if err := h.databaseClient.ReviseNote(ctx, request.ID, text, language); err != nil {
+		zap.L().Error("failed to revise note", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if err := h.reviseMessage(ctx, note, language); err != nil {
+		zap.L().Error("failed to revise pooled note", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	revised, err := h.databaseClient.FindNote(ctx, request.ID, &note.Address)
+	if err != nil {
+		zap.L().Error("failed to find note", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	zap.L().Info("edited note", zap.String("id", request.ID))
+
+	return c.JSON(http.StatusOK, Response{
+		Data: h.newNoteView(revised, &note.Address, nil),
+	})
+}
+
+func (h *Hub) DeleteNote(c echo.Context) error {
+	var request DeleteNoteRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	ctx := c.Request().Context()
+
+	if _, err := h.authorNote(ctx, address, request.ID); err != nil {
+		return authorNoteError(c, request.ID, err)
+	}
+
+	// the minted reward is left alone, deleting only withdraws the note from readers
+	if err := h.databaseClient.DeleteNote(ctx, request.ID); err != nil {
+		zap.L().Error("failed to delete note", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if err := h.removeFromPools(ctx, request.ID); err != nil {
+		zap.L().Error("failed to remove deleted note from pools", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	zap.L().Info("deleted note", zap.String("id", request.ID))
+
+	return c.JSON(http.StatusOK, Response{
+		Data: "ok",
+	})
+}
+
+// authorNote loads a note on behalf of its author, it returns ErrNotAuthor for a note written by someone else.
+func (h *Hub) authorNote(ctx context.Context, address common.Address, messageID string) (*schema.Note, error) {
+	note, err := h.databaseClient.FindNote(ctx, messageID, &address)
+	if err != nil {
+		return nil, err
+	}
+
+	if note.Address != address {
+		return nil, ErrNotAuthor
+	}
+
+	return note, nil
+}
+
+// authorNoteError maps an authorNote failure to its response.
+func authorNoteError(c echo.Context, messageID string, err error) error {
+	switch {
+	case errors.Is(err, database.ErrorRowNotFound):
+		return errorx.NotFoundError(c, fmt.Errorf("note %s not found", messageID))
+	case errors.Is(err, ErrNotAuthor):
+		return errorx.ForbiddenError(c, err)
+	}
+
+	zap.L().Error("failed to find note", zap.String("id", messageID), zap.Error(err))
+
+	return errorx.InternalError(c)
+}
+
+// reviseMessage re-renders the copy handed out by random selection from the revised note store,
+// notes no longer in redis are left alone.
+func (h *Hub) reviseMessage(ctx context.Context, note *schema.Note, language string) error {
+	messageKey := fmt.Sprintf("message:%s", note.MessageID)
+
+	messageJSON, err := h.redisClient.Get(ctx, messageKey).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil
+		}
+
+		return err
+	}
+
+	var message Message
+	if err := json.Unmarshal([]byte(messageJSON), &message); err != nil {
+		return err
+	}
+
+	if err := h.renderMessage(ctx, &message); err != nil {
+		return err
+	}
+
+	updatedMessageJSON, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	pooled, err := h.redisClient.SIsMember(ctx, messagesSet, note.MessageID).Result()
+	if err != nil {
+		return err
+	}
+
+	pipeline := h.redisClient.TxPipeline()
+	pipeline.Set(ctx, messageKey, updatedMessageJSON, redis.KeepTTL)
+
+	if pooled && note.Language != language {
+		if note.Language != "" {
+			pipeline.SRem(ctx, languagePoolKey(note.Language), note.MessageID)
+		}
+
+		if language != "" {
+			pipeline.SAdd(ctx, languagePoolKey(language), note.MessageID)
+		}
+	}
+
+	_, err = pipeline.Exec(ctx)
+
+	return err
+}
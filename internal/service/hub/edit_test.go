@@ -0,0 +1,59 @@
+package hub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestDeletedNoteLeavesSelection(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		filter MessageFilter
+	}{
+		{name: "no filter", filter: MessageFilter{}},
+		{name: "category", filter: MessageFilter{Category: "health"}},
+		{name: "language", filter: MessageFilter{Languages: []string{"en"}}},
+		{name: "category and language", filter: MessageFilter{Category: "health", Languages: []string{"en"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hub, server := newTestHub(t)
+			hub.selector = &UniformSelector{}
+
+			ctx := context.Background()
+			reader := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+			for _, key := range []string{messagesSet, categoryPoolKey("health"), languagePoolKey("en")} {
+				server.SAdd(key, "deleted", "kept")
+			}
+
+			// selecting before the delete caches the category and language intersection
+			if _, err := hub.pickMessageID(ctx, reader, false, tt.filter); err != nil {
+				t.Fatal(err)
+			}
+
+			// DeleteNote withdraws the note from selection through removeFromPools
+			if err := hub.removeFromPools(ctx, "deleted"); err != nil {
+				t.Fatal(err)
+			}
+
+			for range 50 {
+				messageID, err := hub.pickMessageID(ctx, reader, false, tt.filter)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if messageID == "deleted" {
+					t.Fatal("got the deleted note")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package hub
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/brucexc/pray-to-earn/internal/database"
+	"github.com/brucexc/pray-to-earn/internal/service/hub/model/errorx"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/creasty/defaults"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/ecies"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// eciesPublicKeyLength is the size of the uncompressed ephemeral public key that opens an envelope.
+const eciesPublicKeyLength = 65
+
+type GetEncryptionKeyRequest struct {
+	Address common.Address `param:"address" validate:"required"`
+}
+
+type SendEncryptedNoteRequest struct {
+	Recipient common.Address `json:"recipient" validate:"required"`
+	// PublicKey is the recipient key the client encrypted to, it must still be the registered one.
+	PublicKey  hexutil.Bytes `json:"public_key" validate:"required"`
+	Ciphertext hexutil.Bytes `json:"ciphertext" validate:"required"`
+}
+
+type GetEncryptedNotesRequest struct {
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit" validate:"min=1,max=100" default:"20"`
+}
+
+type GetEncryptedNoteRequest struct {
+	ID string `param:"id" validate:"required"`
+}
+
+// RegisterEncryptionKey registers the public key recovered from the request signature, so others can encrypt notes to the signer.
+// Registering again rotates the key, notes sent before keep the key they were encrypted to.
+func (h *Hub) RegisterEncryptionKey(c echo.Context) error {
+	address, publicKey, err := signerKey(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	key := schema.EncryptionKey{
+		Address:   address,
+		PublicKey: crypto.FromECDSAPub(publicKey),
+	}
+
+	if err := h.databaseClient.SaveEncryptionKey(c.Request().Context(), &key); err != nil {
+		zap.L().Error("failed to save encryption key", zap.String("address", address.Hex()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	zap.L().Info("registered encryption key", zap.String("address", address.Hex()))
+
+	return c.JSON(http.StatusOK, Response{
+		Data: key,
+	})
+}
+
+func (h *Hub) GetEncryptionKey(c echo.Context) error {
+	var request GetEncryptionKeyRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	key, err := h.databaseClient.FindEncryptionKey(c.Request().Context(), request.Address)
+	if err != nil {
+		if errors.Is(err, database.ErrorRowNotFound) {
+			return errorx.NotFoundError(c, fmt.Errorf("%s has no encryption key", request.Address.Hex()))
+		}
+
+		zap.L().Error("failed to find encryption key", zap.String("address", request.Address.Hex()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	return c.JSON(http.StatusOK, Response{
+		Data: key,
+	})
+}
+
+// SendEncryptedNote stores an envelope for its recipient, the hub checks its shape but cannot open it.
+func (h *Hub) SendEncryptedNote(c echo.Context) error {
+	var request SendEncryptedNoteRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	if request.Recipient == address {
+		return errorx.ValidationFailedError(c, fmt.Errorf("recipient: must be another address"))
+	}
+
+	if err := verifyEnvelope(request.Ciphertext, h.config.Encryption.MaxEnvelope); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("ciphertext: %w", err))
+	}
+
+	ctx := c.Request().Context()
+
+	key, err := h.databaseClient.FindEncryptionKey(ctx, request.Recipient)
+	if err != nil {
+		if errors.Is(err, database.ErrorRowNotFound) {
+			return errorx.NotFoundError(c, fmt.Errorf("%s has no encryption key", request.Recipient.Hex()))
+		}
+
+		zap.L().Error("failed to find encryption key", zap.String("address", request.Recipient.Hex()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if !bytes.Equal(key.PublicKey, request.PublicKey) {
+		return errorx.ValidationFailedError(c, fmt.Errorf("public_key: is not the registered key of the recipient"))
+	}
+
+	note := schema.EncryptedNote{
+		MessageID:  uuid.New().String(),
+		Address:    address,
+		Recipient:  request.Recipient,
+		PublicKey:  key.PublicKey,
+		Ciphertext: request.Ciphertext,
+	}
+
+	if err := h.databaseClient.SaveEncryptedNote(ctx, &note); err != nil {
+		zap.L().Error("failed to save encrypted note", zap.String("address", address.Hex()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if err := h.notify(ctx, &schema.InboxItem{
+		Address: request.Recipient,
+		Type:    schema.InboxTypeEncrypted,
+		NoteID:  note.MessageID,
+		Actor:   address,
+	}); err != nil {
+		zap.L().Error("failed to notify encrypted note", zap.String("id", note.MessageID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	zap.L().Info("sent encrypted note", zap.String("id", note.MessageID), zap.String("from", address.Hex()), zap.String("to", request.Recipient.Hex()))
+
+	return c.JSON(http.StatusOK, Response{
+		Data: note,
+	})
+}
+
+// GetEncryptedNotes lists the envelopes sent to the signer, newest first.
+func (h *Hub) GetEncryptedNotes(c echo.Context) error {
+	var request GetEncryptedNotesRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := defaults.Set(&request); err != nil {
+		zap.L().Error("set default values for request", zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	cursor, err := parseCursor(request.Cursor)
+	if err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("cursor: %w", err))
+	}
+
+	notes, err := h.databaseClient.FindEncryptedNotes(c.Request().Context(), schema.EncryptedNoteQuery{
+		Recipient: address,
+		Cursor:    cursor,
+		Limit:     request.Limit,
+	})
+	if err != nil {
+		zap.L().Error("failed to find encrypted notes", zap.String("address", address.Hex()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	var nextCursor string
+	if len(notes) == request.Limit {
+		nextCursor = strconv.FormatUint(notes[len(notes)-1].ID, 10)
+	}
+
+	return c.JSON(http.StatusOK, Response{
+		Data:   notes,
+		Cursor: nextCursor,
+	})
+}
+
+// GetEncryptedNote returns one envelope to its sender or recipient, anyone else is told it does not exist.
+func (h *Hub) GetEncryptedNote(c echo.Context) error {
+	var request GetEncryptedNoteRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	note, err := h.databaseClient.FindEncryptedNote(c.Request().Context(), request.ID)
+	if err != nil && !errors.Is(err, database.ErrorRowNotFound) {
+		zap.L().Error("failed to find encrypted note", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if note == nil || (note.Address != address && note.Recipient != address) {
+		return errorx.NotFoundError(c, fmt.Errorf("note %s not found", request.ID))
+	}
+
+	return c.JSON(http.StatusOK, Response{
+		Data: note,
+	})
+}
+
+// verifyEnvelope checks that the ciphertext has the layout of a go-ethereum crypto/ecies envelope on secp256k1:
+// the ephemeral public key, the IV, at least one byte of AES-CTR ciphertext and the HMAC-SHA256 tag.
+// Only the recipient can check the tag, so a well formed envelope may still fail to open.
+func verifyEnvelope(envelope []byte, maxSize int) error {
+	params := ecies.ParamsFromCurve(crypto.S256())
+	overhead := eciesPublicKeyLength + params.BlockSize + params.Hash().Size()
+
+	if len(envelope) <= overhead {
+		return fmt.Errorf("envelope must be longer than %d bytes", overhead)
+	}
+
+	if len(envelope) > maxSize {
+		return fmt.Errorf("envelope must not exceed %d bytes", maxSize)
+	}
+
+	ephemeral, err := crypto.UnmarshalPubkey(envelope[:eciesPublicKeyLength])
+	if err != nil {
+		return fmt.Errorf("invalid ephemeral public key: %w", err)
+	}
+
+	// the cgo build of secp256k1 unmarshals points without checking them
+	if !crypto.S256().IsOnCurve(ephemeral.X, ephemeral.Y) {
+		return fmt.Errorf("invalid ephemeral public key: not on the curve")
+	}
+
+	return nil
+}
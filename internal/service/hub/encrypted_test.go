@@ -0,0 +1,107 @@
+package hub
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/ecies"
+)
+
+func TestVerifyEnvelope(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recipient := ecies.ImportECDSA(key)
+
+	envelope, err := ecies.Encrypt(rand.Reader, &recipient.PublicKey, []byte("peace be with you"), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the shortest envelope holds one byte of ciphertext
+	shortest, err := ecies.Encrypt(rand.Reader, &recipient.PublicKey, []byte("a"), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		envelope func() []byte
+		maxSize  int
+		wantErr  bool
+	}{
+		{
+			name:     "valid",
+			envelope: func() []byte { return envelope },
+			maxSize:  4096,
+		},
+		{
+			name:     "one byte of ciphertext",
+			envelope: func() []byte { return shortest },
+			maxSize:  4096,
+		},
+		{
+			name:     "no ciphertext",
+			envelope: func() []byte { return shortest[:len(shortest)-1] },
+			maxSize:  4096,
+			wantErr:  true,
+		},
+		{
+			name:     "empty",
+			envelope: func() []byte { return nil },
+			maxSize:  4096,
+			wantErr:  true,
+		},
+		{
+			name:     "too large",
+			envelope: func() []byte { return envelope },
+			maxSize:  len(envelope) - 1,
+			wantErr:  true,
+		},
+		{
+			name: "compressed key marker",
+			envelope: func() []byte {
+				tampered := bytes.Clone(envelope)
+				tampered[0] = 0x02
+
+				return tampered
+			},
+			maxSize: 4096,
+			wantErr: true,
+		},
+		{
+			name: "point off the curve",
+			envelope: func() []byte {
+				tampered := bytes.Clone(envelope)
+				tampered[eciesPublicKeyLength-1] ^= 0x01
+
+				return tampered
+			},
+			maxSize: 4096,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := verifyEnvelope(tt.envelope(), tt.maxSize)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error: got %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+
+	// a verified envelope still opens for its recipient
+	plaintext, err := recipient.Decrypt(envelope, nil, nil)
+	if err != nil || string(plaintext) != "peace be with you" {
+		t.Errorf("decrypt: got %q, %v", plaintext, err)
+	}
+}
@@ -0,0 +1,131 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/internal/service/hub/model/errorx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// faucetDrip is what one faucet request sends, 0.5 RSS3.
+var faucetDrip = big.NewInt(5e17)
+
+func faucetCooldownKey(address common.Address) string {
+	return fmt.Sprintf("faucet:cooldown:%s", address.Hex())
+}
+
+var (
+	ErrFaucetCooldown          = errors.New("faucet cooldown")
+	ErrFaucetBalanceSufficient = errors.New("balance is sufficient")
+	ErrFaucetReserveLow        = errors.New("faucet reserve reached")
+	ErrFaucetBudgetExhausted   = errors.New("daily faucet budget exhausted")
+)
+
+// claimFaucet applies the faucet safeguards to a drip for address and reserves its cooldown and a share of the daily budget.
+func (h *Hub) claimFaucet(ctx context.Context, address common.Address) error {
+	policy := h.config.Faucet
+
+	wait, err := h.redisClient.TTL(ctx, faucetCooldownKey(address)).Result()
+	if err != nil {
+		return fmt.Errorf("check faucet cooldown: %w", err)
+	}
+
+	if wait > 0 {
+		return fmt.Errorf("%w, try again in %s", ErrFaucetCooldown, wait.Round(time.Second))
+	}
+
+	balance, err := h.ethereumClient.BalanceAt(ctx, address, nil)
+	if err != nil {
+		return fmt.Errorf("get balance: %w", err)
+	}
+
+	if balance.Cmp(etherToWei(policy.MaxBalance)) > 0 {
+		return fmt.Errorf("%w, balance is above %g RSS3", ErrFaucetBalanceSufficient, policy.MaxBalance)
+	}
+
+	reserve, err := h.ethereumClient.BalanceAt(ctx, serverAdminAddress, nil)
+	if err != nil {
+		return fmt.Errorf("get admin balance: %w", err)
+	}
+
+	if new(big.Int).Sub(reserve, faucetDrip).Cmp(etherToWei(policy.Reserve)) < 0 {
+		zap.L().Warn("faucet reserve reached", zap.String("balance", reserve.String()))
+
+		return ErrFaucetReserveLow
+	}
+
+	claimed, err := h.redisClient.SetNX(ctx, faucetCooldownKey(address), time.Now().Unix(), policy.Cooldown).Result()
+	if err != nil {
+		return fmt.Errorf("start faucet cooldown: %w", err)
+	}
+
+	if !claimed {
+		return fmt.Errorf("%w, a drip to this address is already on its way", ErrFaucetCooldown)
+	}
+
+	// the budget is kept per admin wallet and counted in gwei, so it fits a redis counter
+	withinBudget, err := h.consumeQuotaBy(ctx, quotaScopeFaucet, serverAdminAddress, weiToGwei(faucetDrip), weiToGwei(etherToWei(policy.DailyBudget)))
+	if err != nil {
+		h.releaseFaucetCooldown(ctx, address)
+
+		return fmt.Errorf("consume faucet budget: %w", err)
+	}
+
+	if !withinBudget {
+		h.releaseFaucetCooldown(ctx, address)
+
+		return fmt.Errorf("%w, %g RSS3 handed out", ErrFaucetBudgetExhausted, policy.DailyBudget)
+	}
+
+	return nil
+}
+
+// faucetError maps a claimFaucet refusal to its error code.
+func faucetError(c echo.Context, address common.Address, err error) error {
+	switch {
+	case errors.Is(err, ErrFaucetCooldown):
+		return errorx.FaucetCooldownError(c, err)
+	case errors.Is(err, ErrFaucetBalanceSufficient):
+		return errorx.FaucetBalanceSufficientError(c, err)
+	case errors.Is(err, ErrFaucetReserveLow):
+		return errorx.FaucetReserveLowError(c, err)
+	case errors.Is(err, ErrFaucetBudgetExhausted):
+		return errorx.FaucetBudgetExhaustedError(c, err)
+	}
+
+	zap.L().Error("failed to claim faucet", zap.String("address", address.Hex()), zap.Error(err))
+
+	return errorx.InternalError(c)
+}
+
+// releaseFaucet hands back the cooldown and budget claimed for a drip that was never sent.
+func (h *Hub) releaseFaucet(ctx context.Context, address common.Address) {
+	h.releaseFaucetCooldown(ctx, address)
+
+	if err := h.refundQuota(ctx, quotaScopeFaucet, serverAdminAddress, weiToGwei(faucetDrip)); err != nil {
+		zap.L().Error("failed to refund faucet budget", zap.Error(err))
+	}
+}
+
+func (h *Hub) releaseFaucetCooldown(ctx context.Context, address common.Address) {
+	if err := h.redisClient.Del(ctx, faucetCooldownKey(address)).Err(); err != nil {
+		zap.L().Error("failed to release faucet cooldown", zap.String("address", address.Hex()), zap.Error(err))
+	}
+}
+
+func etherToWei(amount float64) *big.Int {
+	wei, _ := new(big.Float).Mul(big.NewFloat(amount), big.NewFloat(params.Ether)).Int(nil)
+
+	return wei
+}
+
+func weiToGwei(amount *big.Int) int64 {
+	return new(big.Int).Quo(amount, big.NewInt(params.GWei)).Int64()
+}
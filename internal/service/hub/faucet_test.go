@@ -0,0 +1,68 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+)
+
+func TestFaucetError(t *testing.T) {
+	t.Parallel()
+
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "cooldown", err: fmt.Errorf("%w, try again in 1m0s", ErrFaucetCooldown), want: http.StatusTooManyRequests},
+		{name: "balance sufficient", err: ErrFaucetBalanceSufficient, want: http.StatusBadRequest},
+		{name: "reserve low", err: ErrFaucetReserveLow, want: http.StatusServiceUnavailable},
+		{name: "budget exhausted", err: ErrFaucetBudgetExhausted, want: http.StatusServiceUnavailable},
+		{name: "unexpected", err: errors.New("connection refused"), want: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/faucet", nil), recorder)
+
+			if err := faucetError(c, address, tt.err); err != nil {
+				t.Fatal(err)
+			}
+
+			if recorder.Code != tt.want {
+				t.Errorf("got status %d, want %d", recorder.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaimFaucetCooldown(t *testing.T) {
+	t.Parallel()
+
+	hub, server := newTestHub(t)
+
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	// the cooldown is checked before any balance, so no ethereum client is needed
+	if err := server.Set(faucetCooldownKey(address), "1"); err != nil {
+		t.Fatal(err)
+	}
+
+	server.SetTTL(faucetCooldownKey(address), time.Hour)
+
+	if err := hub.claimFaucet(context.Background(), address); !errors.Is(err, ErrFaucetCooldown) {
+		t.Errorf("got %v, want %v", err, ErrFaucetCooldown)
+	}
+}
@@ -6,7 +6,7 @@ import (
 )
 
 var Module = fx.Options(
-	//fx.Provide(provider.ProvideDatabaseClient),
+	fx.Provide(provider.ProvideDatabaseClient),
 	fx.Provide(provider.ProvideEthereumClient),
 	fx.Provide(provider.ProvideRedisClient),
 )
@@ -6,6 +6,7 @@ import (
 	"github.com/brucexc/pray-to-earn/contract"
 	"github.com/brucexc/pray-to-earn/contract/pray"
 	"github.com/brucexc/pray-to-earn/internal/config"
+	"github.com/brucexc/pray-to-earn/internal/database"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -17,11 +18,14 @@ import (
 )
 
 type Hub struct {
-	//databaseClient *database.Client
+	databaseClient *database.Client
+	config         *config.File
 	prayContract   *pray.Pray
 	auth           *bind.TransactOpts
 	ethereumClient *ethclient.Client
 	redisClient    *redis.Client
+	selector       Selector
+	humanVerifier  HumanVerifier
 }
 
 var _ echo.Validator = (*Validator)(nil)
@@ -38,12 +42,22 @@ func (v *Validator) Validate(i interface{}) error {
 	return v.validate.Struct(i)
 }
 
-func NewHub(ctx context.Context, conf config.File, ethereumClient *ethclient.Client, redisClient *redis.Client) (*Hub, error) {
+func NewHub(ctx context.Context, conf config.File, databaseClient *database.Client, ethereumClient *ethclient.Client, redisClient *redis.Client) (*Hub, error) {
 	prayContract, err := pray.NewPray(contract.AddressPray, ethereumClient)
 	if err != nil {
 		return nil, fmt.Errorf("new pray contract: %w", err)
 	}
 
+	selector, err := NewSelector(conf.Selection, redisClient)
+	if err != nil {
+		return nil, fmt.Errorf("new selector: %w", err)
+	}
+
+	humanVerifier, err := NewHumanVerifier(conf.Human)
+	if err != nil {
+		return nil, fmt.Errorf("new human verifier: %w", err)
+	}
+
 	privateKey, _ := crypto.HexToECDSA(conf.AdminKey)
 
 	auth, _ := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(2331))
@@ -53,9 +67,13 @@ func NewHub(ctx context.Context, conf config.File, ethereumClient *ethclient.Cli
 	auth.GasLimit = uint64(300000)
 
 	return &Hub{
+		config:         &conf,
+		databaseClient: databaseClient,
 		redisClient:    redisClient,
 		prayContract:   prayContract,
 		auth:           auth,
 		ethereumClient: ethereumClient,
+		selector:       selector,
+		humanVerifier:  humanVerifier,
 	}, nil
 }
@@ -0,0 +1,31 @@
+package hub
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/brucexc/pray-to-earn/internal/config"
+	"github.com/creasty/defaults"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestHub returns a hub backed by an in-memory redis and the default config,
+// tests reach the server to move its clock or inspect keys.
+func newTestHub(t *testing.T) (*Hub, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+
+	redisClient := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = redisClient.Close() })
+
+	var conf config.File
+	if err := defaults.Set(&conf); err != nil {
+		t.Fatal(err)
+	}
+
+	return &Hub{
+		config:      &conf,
+		redisClient: redisClient,
+	}, server
+}
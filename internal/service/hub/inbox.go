@@ -0,0 +1,162 @@
+package hub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/internal/service/hub/model/errorx"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/creasty/defaults"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type GetInboxRequest struct {
+	UnreadOnly bool   `query:"unread_only"`
+	Cursor     string `query:"cursor"`
+	Limit      int    `query:"limit" validate:"min=1,max=100" default:"20"`
+}
+
+type GetInboxResponse struct {
+	Unread int64               `json:"unread"`
+	Items  []*schema.InboxItem `json:"items"`
+}
+
+type ReadInboxRequest struct {
+	IDs []uint64 `json:"ids" validate:"max=100"`
+	All bool     `json:"all"`
+}
+
+type ReadInboxResponse struct {
+	Updated int64 `json:"updated"`
+	Unread  int64 `json:"unread"`
+}
+
+func (h *Hub) GetInbox(c echo.Context) error {
+	var request GetInboxRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := defaults.Set(&request); err != nil {
+		zap.L().Error("set default values for request", zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	cursor, err := parseCursor(request.Cursor)
+	if err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("cursor: %w", err))
+	}
+
+	items, err := h.databaseClient.FindInboxItems(c.Request().Context(), schema.InboxQuery{
+		Address:    address,
+		UnreadOnly: request.UnreadOnly,
+		Cursor:     cursor,
+		Limit:      request.Limit,
+	})
+	if err != nil {
+		zap.L().Error("failed to find inbox items", zap.String("address", address.Hex()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	unread, err := h.databaseClient.CountUnreadInboxItems(c.Request().Context(), address)
+	if err != nil {
+		zap.L().Error("failed to count unread inbox items", zap.String("address", address.Hex()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	var nextCursor string
+	if len(items) == request.Limit {
+		nextCursor = strconv.FormatUint(items[len(items)-1].ID, 10)
+	}
+
+	return c.JSON(http.StatusOK, Response{
+		Data: GetInboxResponse{
+			Unread: unread,
+			Items:  items,
+		},
+		Cursor: nextCursor,
+	})
+}
+
+func (h *Hub) ReadInbox(c echo.Context) error {
+	var request ReadInboxRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	// an empty id list would otherwise silently mark everything as read
+	if len(request.IDs) == 0 && !request.All {
+		return errorx.ValidationFailedError(c, fmt.Errorf("ids: must not be empty unless all is set"))
+	}
+
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	ids := request.IDs
+	if request.All {
+		ids = nil
+	}
+
+	updated, err := h.databaseClient.MarkInboxItemsRead(c.Request().Context(), address, ids)
+	if err != nil {
+		zap.L().Error("failed to mark inbox items read", zap.String("address", address.Hex()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	unread, err := h.databaseClient.CountUnreadInboxItems(c.Request().Context(), address)
+	if err != nil {
+		zap.L().Error("failed to count unread inbox items", zap.String("address", address.Hex()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	return c.JSON(http.StatusOK, Response{
+		Data: ReadInboxResponse{
+			Updated: updated,
+			Unread:  unread,
+		},
+	})
+}
+
+// notify records an inbox item and applies the retention limits of the recipient's inbox.
+func (h *Hub) notify(ctx context.Context, item *schema.InboxItem) error {
+	if item.Address == item.Actor {
+		return nil
+	}
+
+	if err := h.databaseClient.SaveInboxItem(ctx, item); err != nil {
+		return fmt.Errorf("save inbox item: %w", err)
+	}
+
+	before := time.Now().Add(-h.config.Inbox.Retention)
+	if err := h.databaseClient.PruneInbox(ctx, item.Address, h.config.Inbox.MaxItems, before); err != nil {
+		return fmt.Errorf("prune inbox: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/brucexc/pray-to-earn/internal/database"
+	"github.com/brucexc/pray-to-earn/internal/service/hub/model/errorx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type IntercedeRequest struct {
+	ID string `param:"id" validate:"required"`
+}
+
+type IntercedeResponse struct {
+	Intercessions int64        `json:"intercessions"`
+	Reward        *big.Int     `json:"reward"`
+	TxHash        *common.Hash `json:"tx_hash,omitempty"`
+	// Pending marks a reward whose mint failed and is left for the mint retrier.
+	Pending bool `json:"pending,omitempty"`
+}
+
+// Intercede records that the signer prayed for a note, which is allowed once per address and note.
+func (h *Hub) Intercede(c echo.Context) error {
+	var request IntercedeRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	ctx := c.Request().Context()
+
+	note, err := h.databaseClient.FindNote(ctx, request.ID, &address)
+	if err != nil && !errors.Is(err, database.ErrorRowNotFound) {
+		zap.L().Error("failed to find note", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if note == nil || note.Hidden {
+		return errorx.NotFoundError(c, fmt.Errorf("note %s not found", request.ID))
+	}
+
+	if err := h.checkCircleReader(c, note, address); err != nil {
+		return circleReaderError(c, note.MessageID, err)
+	}
+
+	if note.Address == address {
+		return errorx.BadParamsError(c, fmt.Errorf("cannot intercede for your own note"))
+	}
+
+	created, count, err := h.databaseClient.SaveIntercession(ctx, request.ID, address)
+	if err != nil {
+		zap.L().Error("failed to save intercession", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if !created {
+		return errorx.ConflictError(c, fmt.Errorf("already interceded for note %s", request.ID))
+	}
+
+	response := IntercedeResponse{
+		Intercessions: count,
+		Reward:        big.NewInt(0),
+	}
+
+	// only notes actually handed out to the address are rewarded, so ids scraped from search or feeds cannot be farmed
+	served, err := h.redisClient.SIsMember(ctx, seenKey(address), request.ID).Result()
+	if err != nil {
+		zap.L().Error("failed to check served note", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if served && h.config.Intercession.Reward > 0 {
+		withinQuota, err := h.consumeQuota(ctx, quotaScopeIntercession, address, h.config.Intercession.DailyQuota)
+		if err != nil {
+			zap.L().Error("failed to consume intercession quota", zap.Error(err))
+
+			return errorx.InternalError(c)
+		}
+
+		if withinQuota {
+			amount := wholeTokens(h.config.Intercession.Reward)
+
+			// the intercession is already recorded, so a failed mint is deferred rather than failing the request
+			txHash, minted, err := h.mintOrDefer(ctx, address, amount, "intercession:"+request.ID)
+			switch {
+			case err != nil:
+				zap.L().Error("failed to defer intercession reward", zap.String("to", address.Hex()), zap.Error(err))
+
+				if err := h.refundQuota(ctx, quotaScopeIntercession, address, 1); err != nil {
+					zap.L().Error("failed to refund intercession quota", zap.String("address", address.Hex()), zap.Error(err))
+				}
+			case minted:
+				response.Reward, response.TxHash = amount, &txHash
+			default:
+				response.Reward, response.Pending = amount, true
+			}
+		}
+	}
+
+	zap.L().Info("interceded for note", zap.String("id", request.ID), zap.String("address", address.Hex()), zap.Any("reward", response.Reward))
+
+	return c.JSON(http.StatusOK, Response{
+		Data: response,
+	})
+}
@@ -12,8 +12,10 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/brucexc/pray-to-earn/contract"
+	"github.com/brucexc/pray-to-earn/internal/database"
+	"github.com/brucexc/pray-to-earn/internal/langdetect"
 	"github.com/brucexc/pray-to-earn/internal/service/hub/model/errorx"
+	"github.com/brucexc/pray-to-earn/schema"
 	"github.com/creasty/defaults"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
@@ -24,23 +26,54 @@ import (
 
 type KnockRequest struct {
 	Address common.Address `json:"address" validate:"required"`
-	Note    string         `json:"note"`
+	Note    string         `json:"note" validate:"required_with=Circle RevealAt Recipient"`
+	// ExpiresIn is how many seconds the note stays in the random pool, zero means the configured default.
+	ExpiresIn int64    `json:"expires_in" validate:"gte=0"`
+	Category  string   `json:"category"`
+	Tags      []string `json:"tags"`
+	// FilterCategory limits the note handed back to one category.
+	FilterCategory string `json:"filter_category"`
+	// Language is the preferred language of the note handed back, the Accept-Language header is used when empty.
+	Language string `json:"language"`
+	// Anonymous serves the note under a pseudonym instead of the address prefix.
+	Anonymous bool `json:"anonymous"`
+	// Circle shares the note with a private circle only, the note handed back then comes from the same circle.
+	Circle string `json:"circle"`
+	// RevealAt seals the note as a time capsule until this unix time, its expiry counts from the reveal.
+	RevealAt int64 `json:"reveal_at" validate:"gte=0"`
+	// Recipient directs the note to one address, it lands in their inbox and is offered to them before any other note.
+	Recipient *common.Address `json:"recipient" validate:"required_with=Gift"`
+	// Gift is minted to the recipient along with the note, in whole PRAY tokens.
+	Gift int64 `json:"gift" validate:"gte=0"`
+	// GiftTxHash is the transaction in which the sender burned the gift, one burn funds one gift.
+	GiftTxHash *common.Hash `json:"gift_tx_hash" validate:"required_with=Gift"`
+	// Challenge is the solved proof-of-work challenge, required when the challenge policy is enabled.
+	Challenge *ChallengeSolution `json:"challenge"`
 }
 
 type ReplyRequest struct {
 	ID      string         `json:"id" validate:"required"`
 	Note    string         `json:"note" validate:"required"`
 	Address common.Address `json:"address" validate:"required"`
+	// Anonymous serves the reply under a pseudonym instead of the address prefix.
+	Anonymous bool `json:"anonymous"`
 }
 
 type Response struct {
-	Data any `json:"data"`
+	Data   any    `json:"data"`
+	Cursor string `json:"cursor,omitempty"`
 }
 
 type KnockResponse struct {
-	TotalTokens *big.Int `json:"total_tokens"`
-	AddTokens   *big.Int `json:"add_tokens"`
-	Note        *Message `json:"note"`
+	TotalTokens *big.Int      `json:"total_tokens"`
+	AddTokens   *big.Int      `json:"add_tokens"`
+	Note        *Message      `json:"note"`
+	Spam        *SpamDecision `json:"spam,omitempty"`
+	Gift        *DirectedGift `json:"gift,omitempty"`
+	// Pending marks a reward whose mint failed and is left for the mint retrier.
+	Pending bool `json:"pending,omitempty"`
+	// Chain is the chain prayer the knocker is asked to add a line to, it is only handed to signed knocks.
+	Chain *schema.Chain `json:"chain,omitempty"`
 }
 
 type Message struct {
@@ -52,6 +85,10 @@ type Message struct {
 type PeekNoteRequest struct {
 	Address common.Address `json:"address" validate:"required"`
 	TxHash  common.Hash    `json:"tx_hash" validate:"required"`
+	// Category asks for a note of one category, which costs the configured premium on top of the price.
+	Category string `json:"category"`
+	// Language is the preferred language of the note, the Accept-Language header is used when empty.
+	Language string `json:"language"`
 }
 
 type PeekNoteResponse struct {
@@ -69,6 +106,9 @@ type FaucetResponse struct {
 
 const messagesSet = "messages_set"
 
+// servePickAttempts bounds how often a pick is retried when the picked note already left redis.
+const servePickAttempts = 5
+
 var zeroAddress = common.HexToAddress("0x0000000000000000000000000000000000000000")
 var peekNodePrice = big.NewInt(1).Mul(big.NewInt(1e18), big.NewInt(10))
 var serverAdminAddress = common.HexToAddress("0xBd7537Df4991ef4ABc245e48989C5beE6A56fC61")
@@ -90,44 +130,285 @@ func (h *Hub) Knock(c echo.Context) error {
 		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
 	}
 
-	// rate limit
-	success, err := h.redisClient.SetNX(c.Request().Context(), request.Address.String(), 1, 5*time.Second).Result()
-	if err != nil || !success {
-		return errorx.TooManyRequestError(c, fmt.Errorf("too many requests"))
+	if request.Note != "" {
+		note, err := sanitizeNote(h.config.Note, request.Note)
+		if err != nil {
+			return errorx.ValidationFailedError(c, fmt.Errorf("note: %w", err))
+		}
+
+		request.Note = note
+	}
+
+	if err := h.validateCategory(request.Category); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("category: %w", err))
+	}
+
+	if err := h.validateCategory(request.FilterCategory); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("filter_category: %w", err))
+	}
+
+	tags, err := sanitizeTags(h.config.Category.MaxTags, request.Tags)
+	if err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("tags: %w", err))
+	}
+
+	expiresIn := h.config.Expiry.Default
+	if request.ExpiresIn > 0 {
+		expiresIn = time.Duration(request.ExpiresIn) * time.Second
+	}
+
+	if expiresIn > h.config.Expiry.Max {
+		return errorx.ValidationFailedError(c, fmt.Errorf("expires_in: must not exceed %d seconds", int64(h.config.Expiry.Max.Seconds())))
+	}
+
+	publishAt := time.Now()
+
+	if request.RevealAt > 0 {
+		publishAt = time.Unix(request.RevealAt, 0)
+
+		if !publishAt.After(time.Now()) || time.Until(publishAt) > h.config.Capsule.MaxDelay {
+			return errorx.ValidationFailedError(c, fmt.Errorf("reveal_at: must be in the future and within %s", h.config.Capsule.MaxDelay))
+		}
+	}
+
+	// knocking into a circle must be signed by a joined member
+	if request.Circle != "" {
+		address, err := signer(c)
+		if err != nil {
+			return errorx.UnauthorizedError(c, err)
+		}
+
+		member, err := h.isCircleMember(c.Request().Context(), request.Circle, address)
+		if err != nil {
+			zap.L().Error("failed to check circle membership", zap.String("circle", request.Circle), zap.Error(err))
+
+			return errorx.InternalError(c)
+		}
+
+		if !member || address != request.Address {
+			return errorx.ForbiddenError(c, fmt.Errorf("only members can knock into circle %s", request.Circle))
+		}
+	}
+
+	if request.Recipient != nil {
+		if *request.Recipient == request.Address || *request.Recipient == zeroAddress {
+			return errorx.ValidationFailedError(c, fmt.Errorf("recipient: must be another address"))
+		}
+
+		if request.Gift > h.config.Gift.MaxPerNote {
+			return errorx.ValidationFailedError(c, fmt.Errorf("gift: must not exceed %d tokens", h.config.Gift.MaxPerNote))
+		}
+
+		// the sender pays for a gift, so it must be signed by the sender and funded by their burn
+		if request.Gift > 0 {
+			sender, err := signer(c)
+			if err != nil {
+				return errorx.UnauthorizedError(c, err)
+			}
+
+			if sender != request.Address {
+				return errorx.ForbiddenError(c, fmt.Errorf("gifts must be signed by the sender"))
+			}
+
+			if err := h.findBurn(c.Request().Context(), *request.GiftTxHash, request.Address, wholeTokens(request.Gift)); err != nil {
+				if errors.Is(err, ErrBadPayment) {
+					return errorx.BadPaymentError(c, err)
+				}
+
+				return errorx.ValidationFailedError(c, fmt.Errorf("gift_tx_hash: %w", err))
+			}
+		}
+
+		// a note shared with a circle can only be directed to a fellow member
+		if request.Circle != "" {
+			member, err := h.isCircleMember(c.Request().Context(), request.Circle, *request.Recipient)
+			if err != nil {
+				zap.L().Error("failed to check circle membership", zap.String("circle", request.Circle), zap.Error(err))
+
+				return errorx.InternalError(c)
+			}
+
+			if !member {
+				return errorx.ValidationFailedError(c, fmt.Errorf("recipient: must be a member of circle %s", request.Circle))
+			}
+		}
+	}
+
+	rule := h.config.Human.Knock
+	if err := h.checkHuman(c, request.Address, rule.Always || (rule.MinGift > 0 && request.Gift >= rule.MinGift)); err != nil {
+		return humanError(c, request.Address, err)
+	}
+
+	if h.config.Challenge.Enabled {
+		if err := h.verifyChallenge(c.Request().Context(), c.RealIP(), request.Address, request.Challenge); err != nil {
+			return errorx.ValidationFailedError(c, fmt.Errorf("challenge: %w", err))
+		}
 	}
 
 	mintTokens := big.NewInt(1e18)
-	var otherNote *Message
+	mintReason := "knock:" + request.Address.Hex()
+	var (
+		otherNote *Message
+		spam      *SpamDecision
+		gift      *DirectedGift
+		pending   bool
+	)
+	if request.Note != "" {
+		spam, err = h.checkDuplicate(c.Request().Context(), request.Address, request.Note)
+		if err != nil {
+			zap.L().Error("failed to check duplicate note", zap.Error(err))
+
+			return errorx.InternalError(c)
+		}
+
+		switch {
+		case !spam.Duplicate:
+			// mint 5-10 tokens
+			mintTokens = big.NewInt(1).Mul(big.NewInt(1e18), big.NewInt(int64(rand.New(rand.NewSource(time.Now().UnixNano())).Intn(6)+5)))
+		case h.config.Spam.Reward == "none":
+			mintTokens = big.NewInt(0)
+		}
+
+		// gifts ride on delivered notes only, within the daily budgets of the sender and the server
+		if request.Gift > 0 && !spam.Duplicate {
+			if err := h.claimGift(c.Request().Context(), request.Address, *request.GiftTxHash, request.Gift); err != nil {
+				return giftError(c, err)
+			}
+
+			gift = &DirectedGift{
+				Recipient: *request.Recipient,
+				Amount:    wholeTokens(request.Gift),
+			}
+		}
+	}
+
+	// nothing rejects the knock from here on, so only an accepted knock spends its challenge
+	if h.config.Challenge.Enabled {
+		if err := h.spendChallenge(c.Request().Context(), c.RealIP(), request.Address, request.Challenge); err != nil {
+			if gift != nil {
+				h.releaseGift(c.Request().Context(), request.Address, *request.GiftTxHash, request.Gift)
+			}
+
+			if errors.Is(err, ErrChallengeSpent) {
+				return errorx.ValidationFailedError(c, fmt.Errorf("challenge: %w", err))
+			}
+
+			zap.L().Error("failed to spend challenge", zap.String("address", request.Address.Hex()), zap.Error(err))
+
+			return errorx.InternalError(c)
+		}
+	}
+
 	if request.Note != "" {
-		// mint 5-10 tokens
-		mintTokens = big.NewInt(1).Mul(big.NewInt(1e18), big.NewInt(int64(rand.New(rand.NewSource(time.Now().UnixNano())).Intn(6)+5)))
+		// circle notes earn rewards under their own daily quota
+		if request.Circle != "" && mintTokens.Sign() > 0 {
+			withinQuota, err := h.consumeQuota(c.Request().Context(), quotaScopeCircle, request.Address, h.config.Circle.DailyQuota)
+			if err != nil {
+				zap.L().Error("failed to consume circle quota", zap.Error(err))
+
+				return errorx.InternalError(c)
+			}
+
+			if !withinQuota {
+				mintTokens = big.NewInt(0)
+			}
+		}
+
+		// near-duplicates are kept but never handed out to other users
+		draft := schema.Note{
+			Address:   request.Address,
+			Note:      request.Note,
+			Category:  request.Category,
+			Tags:      tags,
+			Language:  langdetect.Detect(request.Note),
+			Anonymous: request.Anonymous,
+			Circle:    request.Circle,
+			RevealAt:  request.RevealAt,
+			ExpiresAt: publishAt.Add(expiresIn).Unix(),
+			Recipient: request.Recipient,
+		}
+
+		if stored, err := h.storeMessage(c.Request().Context(), &draft, !spam.Duplicate); err != nil {
+			zap.L().Error("failed to store note", zap.Error(err))
+
+			// the recipient never gets the note, so they do not get the gift either
+			if gift != nil {
+				h.releaseGift(c.Request().Context(), request.Address, *request.GiftTxHash, request.Gift)
+			}
+
+			gift = nil
+		} else {
+			mintReason = "knock:" + stored.ID
 
-		storeNote := fmt.Sprintf("%s %s: %s", time.Now().Format("2006-01-02 15:04:05"), request.Address.Hex()[:8], request.Note)
-		_, _ = h.storeMessage(c.Request().Context(), storeNote)
-		otherNote, _ = h.getRandomMessage(c.Request().Context())
+			if err := h.recordFingerprint(c.Request().Context(), request.Address, request.Note); err != nil {
+				zap.L().Error("failed to record fingerprint", zap.String("address", request.Address.Hex()), zap.Error(err))
+			}
+		}
+
+		otherNote, _ = h.getRandomMessage(c.Request().Context(), request.Address, false, MessageFilter{
+			Category:  request.FilterCategory,
+			Languages: preferredLanguages(c, request.Language),
+			Circle:    request.Circle,
+		})
+	}
+
+	// the note is already stored and delivered, so the reward is deferred rather than failing the knock and stranding its gift
+	if mintTokens.Sign() > 0 {
+		txHash, minted, err := h.mintOrDefer(c.Request().Context(), request.Address, mintTokens, mintReason)
+		switch {
+		case err != nil:
+			zap.L().Error("failed to defer knock reward", zap.String("to", request.Address.Hex()), zap.Error(err))
+
+			mintTokens = big.NewInt(0)
+		case !minted:
+			pending = true
+		default:
+			zap.L().Info("minted tokens", zap.String("to", request.Address.Hex()), zap.Any("quantity", mintTokens),
+				zap.String("tx_hash", txHash.Hex()),
+				zap.String("note", request.Note), zap.Any("other_note", otherNote))
+		}
+	} else {
+		zap.L().Info("skipped minting for duplicate note or exhausted quota", zap.String("to", request.Address.Hex()),
+			zap.String("note", request.Note), zap.Any("spam", spam))
 	}
 
-	tx, err := h.prayContract.Mint(h.auth, request.Address, mintTokens)
-	if err != nil {
-		return errorx.InternalError(c)
+	if gift != nil {
+		txHash, minted, err := h.mintOrDefer(c.Request().Context(), gift.Recipient, gift.Amount, "gift:"+request.GiftTxHash.Hex())
+		if err != nil {
+			zap.L().Error("failed to mint gift", zap.String("to", gift.Recipient.Hex()), zap.Error(err))
+
+			// the gift is not paid at all, so the sender gets the budget and the burn back
+			h.releaseGift(c.Request().Context(), request.Address, *request.GiftTxHash, request.Gift)
+
+			return errorx.InternalError(c)
+		}
+
+		gift.TxHash = txHash
+		gift.Pending = !minted
+
+		zap.L().Info("minted gift", zap.String("from", request.Address.Hex()), zap.String("to", gift.Recipient.Hex()),
+			zap.Any("quantity", gift.Amount), zap.String("tx_hash", txHash.Hex()))
 	}
 
-	receipt, err := bind.WaitMined(c.Request().Context(), h.ethereumClient, tx)
-	if err != nil || receipt.Status != types.ReceiptStatusSuccessful {
-		return errorx.InternalError(c)
+	// chains are public, they are never passed on from inside a circle
+	var chain *schema.Chain
+	if knocker, _ := optionalSigner(c); knocker != nil && *knocker == request.Address && request.Circle == "" {
+		if chain, err = h.assignChain(c.Request().Context(), request.Address); err != nil {
+			zap.L().Error("failed to assign chain", zap.String("address", request.Address.Hex()), zap.Error(err))
+		}
 	}
 
 	totalTokens, _ := h.prayContract.BalanceOf(&bind.CallOpts{}, request.Address)
 
-	zap.L().Info("minted tokens", zap.String("to", request.Address.Hex()), zap.Any("quantity", mintTokens),
-		zap.String("tx_hash", tx.Hash().Hex()),
-		zap.String("note", request.Note), zap.Any("other_note", otherNote))
-
 	return c.JSON(http.StatusOK, Response{
 		Data: KnockResponse{
 			TotalTokens: totalTokens,
 			AddTokens:   mintTokens,
 			Note:        otherNote,
+			Spam:        spam,
+			Gift:        gift,
+			Pending:     pending,
+			Chain:       chain,
 		},
 	})
 }
@@ -149,8 +430,53 @@ func (h *Hub) Reply(c echo.Context) error {
 		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
 	}
 
-	storeNote := fmt.Sprintf("%s %s: %s", time.Now().Format("2006-01-02 15:04:05"), request.Address.Hex()[:8], request.Note)
-	_, _ = h.addReplyToMessage(c.Request().Context(), request.ID, storeNote)
+	note, err := sanitizeNote(h.config.Note, request.Note)
+	if err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("note: %w", err))
+	}
+
+	request.Note = note
+
+	storedNote, err := h.databaseClient.FindNote(c.Request().Context(), request.ID, nil)
+	if err != nil && !errors.Is(err, database.ErrorRowNotFound) {
+		zap.L().Error("failed to find note", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if storedNote != nil {
+		if err := h.checkCircleReader(c, storedNote, request.Address); err != nil {
+			return circleReaderError(c, request.ID, err)
+		}
+	}
+
+	replier, err := optionalSigner(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	reply := schema.Reply{
+		NoteID:    request.ID,
+		Address:   request.Address,
+		Reply:     request.Note,
+		Anonymous: request.Anonymous,
+	}
+
+	// only signed replies to notes actually handed out to the replier share in the answer bonus, as with intercessions
+	if replier != nil && *replier == request.Address {
+		served, err := h.redisClient.SIsMember(c.Request().Context(), seenKey(request.Address), request.ID).Result()
+		if err != nil {
+			zap.L().Error("failed to check served note", zap.String("id", request.ID), zap.Error(err))
+
+			return errorx.InternalError(c)
+		}
+
+		reply.Verified = served
+	}
+
+	if _, err := h.addReplyToMessage(c.Request().Context(), &reply); err != nil {
+		zap.L().Error("failed to store reply", zap.String("id", request.ID), zap.Error(err))
+	}
 
 	zap.L().Info("replied to note", zap.String("id", request.ID), zap.String("note", request.Note))
 
@@ -159,11 +485,48 @@ func (h *Hub) Reply(c echo.Context) error {
 	})
 }
 
-func (h *Hub) storeMessage(ctx context.Context, content string) (*Message, error) {
-	messageID := uuid.New().String()
+// renderNote formats a note or reply the way it is handed out to readers.
+func renderNote(at time.Time, author, note string) string {
+	return fmt.Sprintf("%s %s: %s", at.Format("2006-01-02 15:04:05"), author, note)
+}
+
+// storeMessage keeps the raw note in the note store and publishes it for random selection, unless it is sealed.
+func (h *Hub) storeMessage(ctx context.Context, note *schema.Note, pooled bool) (*Message, error) {
+	note.MessageID = uuid.New().String()
+	note.Hidden = !pooled
+	note.SearchLanguage = h.config.Search.Language
+
+	err := h.databaseClient.SaveNote(ctx, note)
+	if err != nil {
+		return nil, fmt.Errorf("save note: %w", err)
+	}
+
+	err = h.redisClient.SAdd(ctx, authoredKey(note.Address), note.MessageID).Err()
+	if err != nil {
+		return nil, err
+	}
+
+	// sealed capsules stay in the note store only, the unsealer publishes them once they are revealed
+	if note.RevealAt > time.Now().Unix() {
+		if pooled {
+			err = h.redisClient.ZAdd(ctx, messagesSealed, redis.Z{Score: float64(note.RevealAt), Member: note.MessageID}).Err()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return &Message{ID: note.MessageID, Replies: []string{}}, nil
+	}
+
+	return h.publishMessage(ctx, note, pooled)
+}
+
+// publishMessage writes the redis copy of a stored note and adds it to the random selection pools unless it is held back.
+func (h *Hub) publishMessage(ctx context.Context, note *schema.Note, pooled bool) (*Message, error) {
+	messageID := note.MessageID
 	message := &Message{
 		ID:      messageID,
-		Content: content,
+		Content: renderNote(time.Now(), h.authorLabel(nil, note.Address, messageID, note.Anonymous, false), note.Note),
 		Replies: []string{},
 	}
 
@@ -172,29 +535,141 @@ func (h *Hub) storeMessage(ctx context.Context, content string) (*Message, error
 		return nil, err
 	}
 
+	// the archiver removes expired notes, the key ttl is only a safety net in case it falls behind
+	var ttl time.Duration
+	if note.ExpiresAt > 0 {
+		ttl = time.Until(time.Unix(note.ExpiresAt, 0)) + archiveGrace
+	}
+
 	messageKey := fmt.Sprintf("message:%s", messageID)
-	err = h.redisClient.Set(ctx, messageKey, messageJSON, 0).Err()
+	err = h.redisClient.Set(ctx, messageKey, messageJSON, ttl).Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.redisClient.HSet(ctx, messageStatsKey(messageID), statsFieldCreatedAt, time.Now().Unix()).Err()
 	if err != nil {
 		return nil, err
 	}
 
+	if !pooled {
+		return message, nil
+	}
+
+	if note.Recipient != nil {
+		if err := h.deliverDirected(ctx, note); err != nil {
+			return nil, err
+		}
+	}
+
+	if note.Circle != "" {
+		pipeline := h.redisClient.TxPipeline()
+		pipeline.SAdd(ctx, circlePoolKey(note.Circle), messageID)
+		pipeline.HSet(ctx, messagesCircle, messageID, note.Circle)
+
+		if _, err = pipeline.Exec(ctx); err != nil {
+			return nil, err
+		}
+
+		return message, h.scheduleExpiry(ctx, note)
+	}
+
 	err = h.redisClient.SAdd(ctx, "messages_set", messageID).Err()
 	if err != nil {
 		return nil, err
 	}
 
-	return message, nil
+	if note.Category != "" {
+		err = h.redisClient.SAdd(ctx, categoryPoolKey(note.Category), messageID).Err()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if note.Language != "" {
+		err = h.redisClient.SAdd(ctx, languagePoolKey(note.Language), messageID).Err()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return message, h.scheduleExpiry(ctx, note)
+}
+
+// scheduleExpiry hands a pooled note over to the archiver.
+func (h *Hub) scheduleExpiry(ctx context.Context, note *schema.Note) error {
+	if note.ExpiresAt == 0 {
+		return nil
+	}
+
+	return h.redisClient.ZAdd(ctx, messagesExpiry, redis.Z{Score: float64(note.ExpiresAt), Member: note.MessageID}).Err()
+}
+
+// getRandomMessage picks a note the address neither wrote nor has been served before, notes directed to the address come first.
+// Once every note has been served it falls back to already seen notes, unless fresh is required.
+func (h *Hub) getRandomMessage(ctx context.Context, address common.Address, fresh bool, filter MessageFilter) (*Message, error) {
+	for attempt := 0; attempt < servePickAttempts; attempt++ {
+		messageID, err := h.pickMessageID(ctx, address, fresh, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		message, err := h.serveMessage(ctx, address, messageID)
+		if !errors.Is(err, redis.Nil) {
+			return message, err
+		}
+
+		// the note left redis after it was picked, drop what is left of it and pick again
+		if err := h.removeFromPools(ctx, messageID); err != nil {
+			return nil, fmt.Errorf("remove missing note: %w", err)
+		}
+	}
+
+	return nil, ErrNoMessage
 }
 
-func (h *Hub) getRandomMessage(ctx context.Context) (*Message, error) {
-	messageID, err := h.redisClient.SRandMember(ctx, messagesSet).Result()
+func (h *Hub) pickMessageID(ctx context.Context, address common.Address, fresh bool, filter MessageFilter) (string, error) {
+	messageID, err := h.pickDirectedMessageID(ctx, address, filter)
+	if err != nil || messageID != "" {
+		return messageID, err
+	}
+
+	pools, err := h.pools(ctx, filter)
 	if err != nil {
-		if errors.Is(err, redis.Nil) {
-			return nil, fmt.Errorf("no messgae found")
+		return "", err
+	}
+
+	for _, pool := range pools {
+		if messageID, err = h.pickUnseenMessageID(ctx, address, pool); err != nil || messageID != "" {
+			break
 		}
-		return nil, err
 	}
 
+	if err != nil {
+		return "", err
+	}
+
+	if messageID == "" && !fresh {
+		for _, pool := range pools {
+			if messageID, err = h.pickMessageIDExcluding(ctx, pool, authoredKey(address)); err != nil || messageID != "" {
+				break
+			}
+		}
+
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if messageID == "" {
+		return "", ErrNoMessage
+	}
+
+	return messageID, nil
+}
+
+// serveMessage loads the note for the address and marks it as seen.
+func (h *Hub) serveMessage(ctx context.Context, address common.Address, messageID string) (*Message, error) {
 	messageKey := fmt.Sprintf("message:%s", messageID)
 	messageJSON, err := h.redisClient.Get(ctx, messageKey).Result()
 	if err != nil {
@@ -207,10 +682,20 @@ func (h *Hub) getRandomMessage(ctx context.Context) (*Message, error) {
 		return nil, err
 	}
 
+	// authors may have renamed themselves since the note was stored
+	if err := h.renderMessage(ctx, &message); err != nil {
+		return nil, err
+	}
+
+	if err := h.markMessageSeen(ctx, address, messageID); err != nil {
+		return nil, err
+	}
+
 	return &message, nil
 }
 
-func (h *Hub) addReplyToMessage(ctx context.Context, messageID string, reply string) (*Message, error) {
+func (h *Hub) addReplyToMessage(ctx context.Context, reply *schema.Reply) (*Message, error) {
+	messageID := reply.NoteID
 	messageKey := fmt.Sprintf("message:%s", messageID)
 	messageJSON, err := h.redisClient.Get(ctx, messageKey).Result()
 	if err != nil {
@@ -223,14 +708,50 @@ func (h *Hub) addReplyToMessage(ctx context.Context, messageID string, reply str
 		return nil, err
 	}
 
-	message.Replies = append(message.Replies, reply)
+	message.Replies = append(message.Replies, renderNote(time.Now(), h.authorLabel(nil, reply.Address, messageID, reply.Anonymous, false), reply.Reply))
 
 	updatedMessageJSON, err := json.Marshal(message)
 	if err != nil {
 		return nil, err
 	}
 
-	err = h.redisClient.Set(ctx, messageKey, updatedMessageJSON, 0).Err()
+	err = h.redisClient.Set(ctx, messageKey, updatedMessageJSON, redis.KeepTTL).Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.redisClient.HIncrBy(ctx, messageStatsKey(messageID), statsFieldReplies, 1).Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.databaseClient.SaveReply(ctx, reply)
+	if err != nil {
+		return nil, fmt.Errorf("save reply: %w", err)
+	}
+
+	storedNote, err := h.databaseClient.FindNote(ctx, messageID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("find note: %w", err)
+	}
+
+	// the actor of an anonymous reply is masked, so self replies are skipped here rather than in notify
+	if storedNote.Address == reply.Address {
+		return &message, nil
+	}
+
+	actor := reply.Address
+	if reply.Anonymous {
+		actor = zeroAddress
+	}
+
+	err = h.notify(ctx, &schema.InboxItem{
+		Address: storedNote.Address,
+		Type:    schema.InboxTypeReply,
+		NoteID:  messageID,
+		ReplyID: &reply.ID,
+		Actor:   actor,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -255,14 +776,50 @@ func (h *Hub) PeekNote(c echo.Context) error {
 		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
 	}
 
+	if err := h.validateCategory(request.Category); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("category: %w", err))
+	}
+
 	zap.L().Info("peek note", zap.String("tx_hash", request.TxHash.Hex()), zap.String("address", request.Address.Hex()))
 
-	if err := h.verifyTxPayment(c, request); err != nil {
-		return err
+	if err := h.verifyTxPayment(c.Request().Context(), request); err != nil {
+		if errors.Is(err, ErrBadPayment) {
+			return errorx.BadPaymentError(c, err)
+		}
+
+		return errorx.ValidationFailedError(c, err)
+	}
+
+	// a burn pays for one peek only
+	spent, err := h.databaseClient.SaveBurn(c.Request().Context(), &schema.Burn{
+		TransactionHash: request.TxHash,
+		Purpose:         schema.BurnPurposePeek,
+		Address:         request.Address,
+		Amount:          h.peekPrice(request.Category),
+	})
+	if err != nil {
+		zap.L().Error("failed to save burn", zap.String("tx_hash", request.TxHash.Hex()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if !spent {
+		return errorx.BadPaymentError(c, fmt.Errorf("%w: transaction %s was already used", ErrBadPayment, request.TxHash.Hex()))
 	}
 
-	otherNote, err := h.getRandomMessage(c.Request().Context())
+	// a paid peek must always return a note the address has not read yet
+	otherNote, err := h.getRandomMessage(c.Request().Context(), request.Address, true, MessageFilter{
+		Category:  request.Category,
+		Languages: preferredLanguages(c, request.Language),
+	})
 	if err != nil {
+		// nothing was served, so the burn can pay for a later peek
+		h.releasePeek(c.Request().Context(), request.TxHash)
+
+		if errors.Is(err, ErrNoMessage) {
+			return errorx.NotFoundError(c, fmt.Errorf("no unread note left, please try again later"))
+		}
+
 		zap.L().Error("failed to get a random note", zap.Error(err))
 		return errorx.InternalError(c)
 	}
@@ -290,14 +847,23 @@ func (h *Hub) Faucet(c echo.Context) error {
 		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
 	}
 
+	if err := h.checkHuman(c, request.Address, h.config.Human.Faucet.Always); err != nil {
+		return humanError(c, request.Address, err)
+	}
+
+	if err := h.claimFaucet(c.Request().Context(), request.Address); err != nil {
+		return faucetError(c, request.Address, err)
+	}
+
 	zap.L().Info("send 0.5 RSS3 to", zap.String("address", request.Address.Hex()))
 
 	// send 0.5 ether to request.Address, use h.auth.Signer to sign the transaction
 	nonce, _ := h.ethereumClient.NonceAt(c.Request().Context(), serverAdminAddress, nil)
 	gasPrice, _ := h.ethereumClient.SuggestGasPrice(c.Request().Context())
-	sendTx, err := h.auth.Signer(h.auth.From, types.NewTransaction(nonce, request.Address, big.NewInt(5e17), 21000, gasPrice, nil))
+	sendTx, err := h.auth.Signer(h.auth.From, types.NewTransaction(nonce, request.Address, faucetDrip, 21000, gasPrice, nil))
 	if err != nil {
 		zap.L().Error("failed to sign transaction", zap.Error(err))
+		h.releaseFaucet(c.Request().Context(), request.Address)
 		return errorx.InternalError(c)
 	}
 
@@ -305,6 +871,7 @@ func (h *Hub) Faucet(c echo.Context) error {
 
 	if err := h.ethereumClient.SendTransaction(c.Request().Context(), sendTx); err != nil {
 		zap.L().Error("failed to send transaction", zap.Error(err))
+		h.releaseFaucet(c.Request().Context(), request.Address)
 		return errorx.InternalError(c)
 	}
 
@@ -316,24 +883,12 @@ func (h *Hub) Faucet(c echo.Context) error {
 	})
 }
 
-func (h *Hub) verifyTxPayment(c echo.Context, request PeekNoteRequest) error {
-	receipt, err := h.ethereumClient.TransactionReceipt(c.Request().Context(), request.TxHash)
-	if err != nil {
-		return errorx.ValidationFailedError(c, fmt.Errorf("get transaction receipt: %w", err))
-	}
-
-	// check if the transaction is a burn transaction
-	for _, log := range receipt.Logs {
-		if log.Address == contract.AddressPray && log.Topics[0] == contract.TransferEventSig {
-			from := common.HexToAddress(log.Topics[1].Hex())
-			to := common.HexToAddress(log.Topics[2].Hex())
-			amount := new(big.Int).SetBytes(log.Data)
+func (h *Hub) verifyTxPayment(ctx context.Context, request PeekNoteRequest) error {
+	return h.findBurn(ctx, request.TxHash, request.Address, h.peekPrice(request.Category))
+}
 
-			if from == request.Address && to == zeroAddress && amount.Cmp(peekNodePrice) == 0 {
-				zap.L().Info("found payment", zap.Any("from", from), zap.Any("to", to), zap.Any("amount", amount))
-				return nil
-			}
-		}
+func (h *Hub) releasePeek(ctx context.Context, txHash common.Hash) {
+	if err := h.databaseClient.DeleteBurn(context.WithoutCancel(ctx), txHash); err != nil {
+		zap.L().Error("failed to release peek burn", zap.String("tx_hash", txHash.Hex()), zap.Error(err))
 	}
-	return errorx.BadPaymentError(c, fmt.Errorf("payment not found"))
 }
@@ -0,0 +1,34 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestGetRandomMessageSkipsMissingNotes(t *testing.T) {
+	t.Parallel()
+
+	hub, server := newTestHub(t)
+	hub.selector = &UniformSelector{}
+
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	filter := MessageFilter{Category: "health", Languages: []string{"en"}}
+
+	// the note is still pooled but its message is gone, as when it left redis after being picked
+	server.SAdd(messagesSet, "gone")
+	server.SAdd(categoryPoolKey("health"), "gone")
+	server.SAdd(languagePoolKey("en"), "gone")
+
+	if _, err := hub.getRandomMessage(context.Background(), address, true, filter); !errors.Is(err, ErrNoMessage) {
+		t.Fatalf("got %v, want %v", err, ErrNoMessage)
+	}
+
+	for _, key := range []string{messagesSet, categoryPoolKey("health"), intersectionPoolKey("health", "en")} {
+		if ok, _ := server.SIsMember(key, "gone"); ok {
+			t.Errorf("%s: missing note is still pooled", key)
+		}
+	}
+}
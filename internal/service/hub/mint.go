@@ -0,0 +1,187 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// ErrMintPending is returned while a mint transaction of an earlier attempt has not been mined yet.
+var ErrMintPending = errors.New("earlier mint transaction is still pending")
+
+// mint mints PRAY to the address and waits for the transaction to succeed.
+func (h *Hub) mint(ctx context.Context, to common.Address, amount *big.Int) (common.Hash, error) {
+	tx, err := h.prayContract.Mint(h.auth, to, amount)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("send mint transaction: %w", err)
+	}
+
+	receipt, err := bind.WaitMined(ctx, h.ethereumClient, tx)
+	if err != nil {
+		return tx.Hash(), fmt.Errorf("wait for mint transaction: %w", err)
+	}
+
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return tx.Hash(), fmt.Errorf("mint transaction %s reverted", tx.Hash().Hex())
+	}
+
+	return tx.Hash(), nil
+}
+
+// mintOrDefer mints a reward, or records it as a pending mint for RunMintRetrier when the mint fails.
+// It reports whether the reward was minted right away, an error means it could not even be recorded.
+func (h *Hub) mintOrDefer(ctx context.Context, to common.Address, amount *big.Int, reason string) (common.Hash, bool, error) {
+	txHash, err := h.mint(ctx, to, amount)
+	if err == nil {
+		return txHash, true, nil
+	}
+
+	zap.L().Error("failed to mint, deferring", zap.String("reason", reason), zap.String("to", to.Hex()), zap.Error(err))
+
+	pending := schema.PendingMint{
+		Address:   to,
+		Amount:    amount,
+		Reason:    reason,
+		Attempts:  1,
+		LastError: err.Error(),
+	}
+
+	// the transaction may still go through, the retrier checks it before minting again
+	if txHash != (common.Hash{}) {
+		pending.TransactionHashes = []common.Hash{txHash}
+	}
+
+	// the request may have been canceled while waiting for the mint, the record must be kept regardless
+	if err := h.databaseClient.SavePendingMint(context.WithoutCancel(ctx), &pending); err != nil {
+		return common.Hash{}, false, fmt.Errorf("save pending mint: %w", err)
+	}
+
+	return common.Hash{}, false, nil
+}
+
+// RunMintRetrier periodically retries failed mints until the context is canceled.
+func (h *Hub) RunMintRetrier(ctx context.Context) {
+	ticker := time.NewTicker(h.config.Mint.RetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.retryPendingMints(ctx); err != nil {
+				zap.L().Error("failed to retry pending mints", zap.Error(err))
+			}
+		}
+	}
+}
+
+// retryPendingMints retries one batch of pending mints, a mint out of attempts is left for an operator.
+func (h *Hub) retryPendingMints(ctx context.Context) error {
+	mints, err := h.databaseClient.FindPendingMints(ctx, h.config.Mint.MaxAttempts, h.config.Mint.BatchSize)
+	if err != nil {
+		return fmt.Errorf("find pending mints: %w", err)
+	}
+
+	for _, pending := range mints {
+		txHash, err := h.settlePendingMint(ctx, pending)
+		if errors.Is(err, ErrMintPending) {
+			zap.L().Info("waiting for earlier mint transaction", zap.Uint64("id", pending.ID), zap.String("reason", pending.Reason), zap.Error(err))
+
+			continue
+		}
+
+		if err != nil {
+			zap.L().Warn("failed to retry mint", zap.Uint64("id", pending.ID), zap.String("reason", pending.Reason), zap.Int("attempts", pending.Attempts+1), zap.Error(err))
+
+			if err := h.databaseClient.FailPendingMint(ctx, pending.ID, txHash, err.Error()); err != nil {
+				return fmt.Errorf("fail pending mint %d: %w", pending.ID, err)
+			}
+
+			continue
+		}
+
+		if err := h.databaseClient.CompletePendingMint(ctx, pending.ID, txHash); err != nil {
+			return fmt.Errorf("complete pending mint %d: %w", pending.ID, err)
+		}
+
+		zap.L().Info("minted pending mint", zap.Uint64("id", pending.ID), zap.String("reason", pending.Reason), zap.String("to", pending.Address.Hex()), zap.String("tx_hash", txHash.Hex()))
+	}
+
+	return nil
+}
+
+// settlePendingMint mints a pending mint, unless a transaction of an earlier attempt went through after all.
+// It only mints again once every earlier transaction is known to have failed or been dropped.
+func (h *Hub) settlePendingMint(ctx context.Context, pending *schema.PendingMint) (common.Hash, error) {
+	var waiting *common.Hash
+
+	for _, txHash := range pending.TransactionHashes {
+		state, err := h.checkMintTransaction(ctx, txHash)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("check mint transaction %s: %w", txHash.Hex(), err)
+		}
+
+		switch state {
+		case mintTransactionSucceeded:
+			return txHash, nil
+		case mintTransactionPending:
+			waiting = &txHash
+		}
+	}
+
+	if waiting != nil {
+		return common.Hash{}, fmt.Errorf("%w: %s", ErrMintPending, waiting.Hex())
+	}
+
+	return h.mint(ctx, pending.Address, pending.Amount)
+}
+
+type mintTransactionState int
+
+const (
+	mintTransactionFailed mintTransactionState = iota
+	mintTransactionPending
+	mintTransactionSucceeded
+)
+
+// checkMintTransaction tells whether a mint transaction succeeded, failed or is still waiting to be mined.
+// A transaction the node no longer knows about was dropped and counts as failed.
+func (h *Hub) checkMintTransaction(ctx context.Context, txHash common.Hash) (mintTransactionState, error) {
+	receipt, err := h.ethereumClient.TransactionReceipt(ctx, txHash)
+	if err == nil {
+		if receipt.Status == types.ReceiptStatusSuccessful {
+			return mintTransactionSucceeded, nil
+		}
+
+		return mintTransactionFailed, nil
+	}
+
+	if !errors.Is(err, ethereum.NotFound) {
+		return mintTransactionFailed, fmt.Errorf("get receipt: %w", err)
+	}
+
+	if _, _, err := h.ethereumClient.TransactionByHash(ctx, txHash); err != nil {
+		if errors.Is(err, ethereum.NotFound) {
+			return mintTransactionFailed, nil
+		}
+
+		return mintTransactionFailed, fmt.Errorf("get transaction: %w", err)
+	}
+
+	return mintTransactionPending, nil
+}
+
+// wholeTokens converts an amount of whole PRAY tokens into its smallest unit.
+func wholeTokens(amount int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(1e18), big.NewInt(amount))
+}
@@ -0,0 +1,213 @@
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// stubChain answers the receipt and transaction lookups of ethclient from fixed responses, a missing entry is not found.
+type stubChain struct {
+	receipts     map[common.Hash]json.RawMessage
+	transactions map[common.Hash]json.RawMessage
+	// broken makes every lookup fail
+	broken bool
+}
+
+func (s *stubChain) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params []common.Hash   `json:"params"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || len(request.Params) != 1 {
+		http.Error(w, "bad request", http.StatusBadRequest)
+
+		return
+	}
+
+	if s.broken {
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": request.ID, "error": map[string]any{"code": -32000, "message": "unavailable"}})
+
+		return
+	}
+
+	var result json.RawMessage
+
+	switch request.Method {
+	case "eth_getTransactionReceipt":
+		result = s.receipts[request.Params[0]]
+	case "eth_getTransactionByHash":
+		result = s.transactions[request.Params[0]]
+	}
+
+	if result == nil {
+		result = json.RawMessage("null")
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": request.ID, "result": result})
+}
+
+func TestSettlePendingMint(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a signed transaction the node still holds without a receipt
+	pendingTx, err := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil), types.HomesteadSigner{}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pendingJSON, err := pendingTx.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	receipt := func(txHash common.Hash, status uint64) json.RawMessage {
+		data, err := (&types.Receipt{Status: status, TxHash: txHash, Logs: []*types.Log{}}).MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return data
+	}
+
+	var (
+		succeeded = common.HexToHash("0x01")
+		reverted  = common.HexToHash("0x02")
+		pending   = pendingTx.Hash()
+	)
+
+	chain := &stubChain{
+		receipts: map[common.Hash]json.RawMessage{
+			succeeded: receipt(succeeded, types.ReceiptStatusSuccessful),
+			reverted:  receipt(reverted, types.ReceiptStatusFailed),
+		},
+		transactions: map[common.Hash]json.RawMessage{
+			pending: pendingJSON,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		chain   *stubChain
+		hashes  []common.Hash
+		want    common.Hash
+		wantErr error
+		// wantAnyErr is set for failures that carry no sentinel
+		wantAnyErr bool
+	}{
+		{
+			name:   "earlier transaction succeeded",
+			chain:  chain,
+			hashes: []common.Hash{succeeded},
+			want:   succeeded,
+		},
+		{
+			name:    "earlier transaction still pending",
+			chain:   chain,
+			hashes:  []common.Hash{pending},
+			wantErr: ErrMintPending,
+		},
+		{
+			name:    "a reverted attempt does not hide a pending one",
+			chain:   chain,
+			hashes:  []common.Hash{reverted, pending},
+			wantErr: ErrMintPending,
+		},
+		{
+			name:   "any attempt that succeeded settles the mint",
+			chain:  chain,
+			hashes: []common.Hash{reverted, pending, succeeded},
+			want:   succeeded,
+		},
+		{
+			name:       "lookup failure never mints again",
+			chain:      &stubChain{broken: true},
+			hashes:     []common.Hash{pending},
+			wantAnyErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(tt.chain)
+			t.Cleanup(server.Close)
+
+			client, err := ethclient.Dial(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			t.Cleanup(client.Close)
+
+			hub, _ := newTestHub(t)
+			hub.ethereumClient = client
+
+			got, err := hub.settlePendingMint(context.Background(), &schema.PendingMint{
+				Address:           common.HexToAddress("0x0000000000000000000000000000000000000001"),
+				Amount:            wholeTokens(1),
+				TransactionHashes: tt.hashes,
+			})
+
+			switch {
+			case tt.wantAnyErr:
+				if err == nil || errors.Is(err, ErrMintPending) {
+					t.Fatalf("error: got %v, want a lookup failure", err)
+				}
+			case !errors.Is(err, tt.wantErr):
+				t.Fatalf("error: got %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got.Hex(), tt.want.Hex())
+			}
+		})
+	}
+}
+
+func TestCheckMintTransaction(t *testing.T) {
+	t.Parallel()
+
+	dropped := common.HexToHash("0x03")
+
+	server := httptest.NewServer(&stubChain{})
+	t.Cleanup(server.Close)
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(client.Close)
+
+	hub, _ := newTestHub(t)
+	hub.ethereumClient = client
+
+	// a transaction neither mined nor known to the node was dropped
+	state, err := hub.checkMintTransaction(context.Background(), dropped)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state != mintTransactionFailed {
+		t.Errorf("got state %d, want failed", state)
+	}
+}
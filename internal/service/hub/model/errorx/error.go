@@ -12,11 +12,19 @@ type ErrorCode int
 
 const (
 	ErrorCodeBadRequest ErrorCode = iota + 1
-	ErrorCodeValidationFailed
+	ErrorCodeValidateFailed
 	ErrorCodeBadParams
 	ErrorCodeInternalError
 	ErrorCodeBadPayment
 	ErrorTooManyRequest
+	ErrorCodeNotFound
+	ErrorCodeUnauthorized
+	ErrorCodeForbidden
+	ErrorCodeConflict
+	ErrorCodeFaucetCooldown
+	ErrorCodeFaucetBudgetExhausted
+	ErrorCodeFaucetBalanceSufficient
+	ErrorCodeFaucetReserveLow
 )
 
 type ErrorResponse struct {
@@ -35,7 +43,7 @@ func BadRequestError(c echo.Context, err error) error {
 
 func ValidationFailedError(c echo.Context, err error) error {
 	return c.JSON(http.StatusBadRequest, &ErrorResponse{
-		ErrorCode: ErrorCodeValidationFailed,
+		ErrorCode: ErrorCodeValidateFailed,
 		Error:     "Validation failed. Ensure all fields meet the required criteria and try again.",
 		Details:   fmt.Sprintf("%v", err),
 	})
@@ -65,6 +73,70 @@ func TooManyRequestError(c echo.Context, err error) error {
 	})
 }
 
+func NotFoundError(c echo.Context, err error) error {
+	return c.JSON(http.StatusNotFound, &ErrorResponse{
+		ErrorCode: ErrorCodeNotFound,
+		Error:     "The requested resource was not found.",
+		Details:   fmt.Sprintf("%v", err),
+	})
+}
+
+func UnauthorizedError(c echo.Context, err error) error {
+	return c.JSON(http.StatusUnauthorized, &ErrorResponse{
+		ErrorCode: ErrorCodeUnauthorized,
+		Error:     "A valid wallet signature is required.",
+		Details:   fmt.Sprintf("%v", err),
+	})
+}
+
+func ForbiddenError(c echo.Context, err error) error {
+	return c.JSON(http.StatusForbidden, &ErrorResponse{
+		ErrorCode: ErrorCodeForbidden,
+		Error:     "You are not allowed to access this resource.",
+		Details:   fmt.Sprintf("%v", err),
+	})
+}
+
+func ConflictError(c echo.Context, err error) error {
+	return c.JSON(http.StatusConflict, &ErrorResponse{
+		ErrorCode: ErrorCodeConflict,
+		Error:     "The action has already been taken.",
+		Details:   fmt.Sprintf("%v", err),
+	})
+}
+
+func FaucetCooldownError(c echo.Context, err error) error {
+	return c.JSON(http.StatusTooManyRequests, &ErrorResponse{
+		ErrorCode: ErrorCodeFaucetCooldown,
+		Error:     "This address has used the faucet recently, please try again later.",
+		Details:   fmt.Sprintf("%v", err),
+	})
+}
+
+func FaucetBudgetExhaustedError(c echo.Context, err error) error {
+	return c.JSON(http.StatusServiceUnavailable, &ErrorResponse{
+		ErrorCode: ErrorCodeFaucetBudgetExhausted,
+		Error:     "The faucet has handed out its budget for today, please try again tomorrow.",
+		Details:   fmt.Sprintf("%v", err),
+	})
+}
+
+func FaucetBalanceSufficientError(c echo.Context, err error) error {
+	return c.JSON(http.StatusBadRequest, &ErrorResponse{
+		ErrorCode: ErrorCodeFaucetBalanceSufficient,
+		Error:     "This address already holds enough tokens.",
+		Details:   fmt.Sprintf("%v", err),
+	})
+}
+
+func FaucetReserveLowError(c echo.Context, err error) error {
+	return c.JSON(http.StatusServiceUnavailable, &ErrorResponse{
+		ErrorCode: ErrorCodeFaucetReserveLow,
+		Error:     "The faucet is running low, please try again later.",
+		Details:   fmt.Sprintf("%v", err),
+	})
+}
+
 func InternalError(c echo.Context) error {
 	return c.JSON(http.StatusInternalServerError, &ErrorResponse{
 		ErrorCode: ErrorCodeInternalError,
@@ -8,11 +8,11 @@ import (
 	"strings"
 )
 
-const _ErrorCodeName = "bad_requestvalidate_failedbad_paramsinternal_error"
+const _ErrorCodeName = "bad_requestvalidate_failedbad_paramsinternal_errorbad_paymenterror_too_many_requestnot_foundunauthorizedforbiddenconflictfaucet_cooldownfaucet_budget_exhaustedfaucet_balance_sufficientfaucet_reserve_low"
 
-var _ErrorCodeIndex = [...]uint8{0, 11, 26, 36, 50}
+var _ErrorCodeIndex = [...]uint8{0, 11, 26, 36, 50, 61, 83, 92, 104, 113, 121, 136, 159, 184, 202}
 
-const _ErrorCodeLowerName = "bad_requestvalidate_failedbad_paramsinternal_error"
+const _ErrorCodeLowerName = "bad_requestvalidate_failedbad_paramsinternal_errorbad_paymenterror_too_many_requestnot_foundunauthorizedforbiddenconflictfaucet_cooldownfaucet_budget_exhaustedfaucet_balance_sufficientfaucet_reserve_low"
 
 func (i ErrorCode) String() string {
 	i -= 1
@@ -31,22 +31,55 @@ func (ErrorCode) Values() []string {
 func _ErrorCodeNoOp() {
 	var x [1]struct{}
 	_ = x[ErrorCodeBadRequest-(1)]
-	_ = x[ErrorCodeValidationFailed-(2)]
+	_ = x[ErrorCodeValidateFailed-(2)]
 	_ = x[ErrorCodeBadParams-(3)]
 	_ = x[ErrorCodeInternalError-(4)]
+	_ = x[ErrorCodeBadPayment-(5)]
+	_ = x[ErrorTooManyRequest-(6)]
+	_ = x[ErrorCodeNotFound-(7)]
+	_ = x[ErrorCodeUnauthorized-(8)]
+	_ = x[ErrorCodeForbidden-(9)]
+	_ = x[ErrorCodeConflict-(10)]
+	_ = x[ErrorCodeFaucetCooldown-(11)]
+	_ = x[ErrorCodeFaucetBudgetExhausted-(12)]
+	_ = x[ErrorCodeFaucetBalanceSufficient-(13)]
+	_ = x[ErrorCodeFaucetReserveLow-(14)]
 }
 
-var _ErrorCodeValues = []ErrorCode{ErrorCodeBadRequest, ErrorCodeValidationFailed, ErrorCodeBadParams, ErrorCodeInternalError}
+var _ErrorCodeValues = []ErrorCode{ErrorCodeBadRequest, ErrorCodeValidateFailed, ErrorCodeBadParams, ErrorCodeInternalError, ErrorCodeBadPayment, ErrorTooManyRequest, ErrorCodeNotFound, ErrorCodeUnauthorized, ErrorCodeForbidden, ErrorCodeConflict, ErrorCodeFaucetCooldown, ErrorCodeFaucetBudgetExhausted, ErrorCodeFaucetBalanceSufficient, ErrorCodeFaucetReserveLow}
 
 var _ErrorCodeNameToValueMap = map[string]ErrorCode{
-	_ErrorCodeName[0:11]:       ErrorCodeBadRequest,
-	_ErrorCodeLowerName[0:11]:  ErrorCodeBadRequest,
-	_ErrorCodeName[11:26]:      ErrorCodeValidationFailed,
-	_ErrorCodeLowerName[11:26]: ErrorCodeValidationFailed,
-	_ErrorCodeName[26:36]:      ErrorCodeBadParams,
-	_ErrorCodeLowerName[26:36]: ErrorCodeBadParams,
-	_ErrorCodeName[36:50]:      ErrorCodeInternalError,
-	_ErrorCodeLowerName[36:50]: ErrorCodeInternalError,
+	_ErrorCodeName[0:11]:    ErrorCodeBadRequest,
+	_ErrorCodeName[11:26]:   ErrorCodeValidateFailed,
+	_ErrorCodeName[26:36]:   ErrorCodeBadParams,
+	_ErrorCodeName[36:50]:   ErrorCodeInternalError,
+	_ErrorCodeName[50:61]:   ErrorCodeBadPayment,
+	_ErrorCodeName[61:83]:   ErrorTooManyRequest,
+	_ErrorCodeName[83:92]:   ErrorCodeNotFound,
+	_ErrorCodeName[92:104]:  ErrorCodeUnauthorized,
+	_ErrorCodeName[104:113]: ErrorCodeForbidden,
+	_ErrorCodeName[113:121]: ErrorCodeConflict,
+	_ErrorCodeName[121:136]: ErrorCodeFaucetCooldown,
+	_ErrorCodeName[136:159]: ErrorCodeFaucetBudgetExhausted,
+	_ErrorCodeName[159:184]: ErrorCodeFaucetBalanceSufficient,
+	_ErrorCodeName[184:202]: ErrorCodeFaucetReserveLow,
+}
+
+var _ErrorCodeLowerNameToValueMap = map[string]ErrorCode{
+	_ErrorCodeLowerName[0:11]:    ErrorCodeBadRequest,
+	_ErrorCodeLowerName[11:26]:   ErrorCodeValidateFailed,
+	_ErrorCodeLowerName[26:36]:   ErrorCodeBadParams,
+	_ErrorCodeLowerName[36:50]:   ErrorCodeInternalError,
+	_ErrorCodeLowerName[50:61]:   ErrorCodeBadPayment,
+	_ErrorCodeLowerName[61:83]:   ErrorTooManyRequest,
+	_ErrorCodeLowerName[83:92]:   ErrorCodeNotFound,
+	_ErrorCodeLowerName[92:104]:  ErrorCodeUnauthorized,
+	_ErrorCodeLowerName[104:113]: ErrorCodeForbidden,
+	_ErrorCodeLowerName[113:121]: ErrorCodeConflict,
+	_ErrorCodeLowerName[121:136]: ErrorCodeFaucetCooldown,
+	_ErrorCodeLowerName[136:159]: ErrorCodeFaucetBudgetExhausted,
+	_ErrorCodeLowerName[159:184]: ErrorCodeFaucetBalanceSufficient,
+	_ErrorCodeLowerName[184:202]: ErrorCodeFaucetReserveLow,
 }
 
 var _ErrorCodeNames = []string{
@@ -54,6 +87,16 @@ var _ErrorCodeNames = []string{
 	_ErrorCodeName[11:26],
 	_ErrorCodeName[26:36],
 	_ErrorCodeName[36:50],
+	_ErrorCodeName[50:61],
+	_ErrorCodeName[61:83],
+	_ErrorCodeName[83:92],
+	_ErrorCodeName[92:104],
+	_ErrorCodeName[104:113],
+	_ErrorCodeName[113:121],
+	_ErrorCodeName[121:136],
+	_ErrorCodeName[136:159],
+	_ErrorCodeName[159:184],
+	_ErrorCodeName[184:202],
 }
 
 // ErrorCodeString retrieves an enum value from the enum constants string name.
@@ -63,7 +106,7 @@ func ErrorCodeString(s string) (ErrorCode, error) {
 		return val, nil
 	}
 
-	if val, ok := _ErrorCodeNameToValueMap[strings.ToLower(s)]; ok {
+	if val, ok := _ErrorCodeLowerNameToValueMap[strings.ToLower(s)]; ok {
 		return val, nil
 	}
 	return 0, fmt.Errorf("%s does not belong to ErrorCode values", s)
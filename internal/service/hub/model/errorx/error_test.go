@@ -0,0 +1,38 @@
+package errorx
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestErrorCodeWireValues pins the codes clients match on, regenerating the enum must not change them.
+func TestErrorCodeWireValues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		code ErrorCode
+		want string
+	}{
+		{ErrorCodeBadRequest, "bad_request"},
+		{ErrorCodeValidateFailed, "validate_failed"},
+		{ErrorCodeBadParams, "bad_params"},
+		{ErrorCodeInternalError, "internal_error"},
+		{ErrorCodeBadPayment, "bad_payment"},
+		{ErrorTooManyRequest, "error_too_many_request"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := json.Marshal(tt.code)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(got) != `"`+tt.want+`"` {
+				t.Errorf("got %s, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/brucexc/pray-to-earn/internal/config"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	urlPolicyStrip  = "strip"
+	urlPolicyReject = "reject"
+)
+
+var (
+	ErrNoteEmpty          = errors.New("must not be empty")
+	ErrNoteTooLong        = errors.New("exceeds the maximum length")
+	ErrNoteInvisibleChar  = errors.New("contains invisible or bidi override characters")
+	ErrNoteCombiningMarks = errors.New("contains too many stacked combining marks")
+	ErrNoteURL            = errors.New("must not contain urls")
+)
+
+var (
+	urlPattern        = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+	horizontalSpaces  = regexp.MustCompile(`[^\S\n]+`)
+	repeatedNewlines  = regexp.MustCompile(`\n{3,}`)
+	spacesAroundBreak = regexp.MustCompile(` ?\n ?`)
+)
+
+// invisibleRunes are rejected outright, they are either zero width or can reorder the rendered text.
+var invisibleRunes = map[rune]struct{}{
+	'\u00ad': {}, // soft hyphen
+	'\u180e': {}, // mongolian vowel separator
+	'\u200b': {}, // zero width space
+	'\u200c': {}, // zero width non-joiner
+	'\u200e': {}, // left-to-right mark
+	'\u200f': {}, // right-to-left mark
+	'\u202a': {}, // left-to-right embedding
+	'\u202b': {}, // right-to-left embedding
+	'\u202c': {}, // pop directional formatting
+	'\u202d': {}, // left-to-right override
+	'\u202e': {}, // right-to-left override
+	'\u2060': {}, // word joiner
+	'\u2066': {}, // left-to-right isolate
+	'\u2067': {}, // right-to-left isolate
+	'\u2068': {}, // first strong isolate
+	'\u2069': {}, // pop directional isolate
+	'\ufeff': {}, // zero width no-break space
+}
+
+// sanitizeNote normalizes a note according to the rules, the returned error is meant to be prefixed with the field name.
+func sanitizeNote(rules *config.Note, note string) (string, error) {
+	// reject oversized payloads before doing any work on them
+	if len(note) > rules.MaxRunes*utf8.UTFMax {
+		return "", fmt.Errorf("%w of %d characters", ErrNoteTooLong, rules.MaxRunes)
+	}
+
+	if !utf8.ValidString(note) {
+		note = strings.ToValidUTF8(note, "")
+	}
+
+	if rules.Normalization == "nfc" {
+		note = norm.NFC.String(note)
+	}
+
+	var (
+		builder   strings.Builder
+		combining int
+	)
+
+	builder.Grow(len(note))
+
+	for _, r := range note {
+		if _, found := invisibleRunes[r]; found {
+			return "", ErrNoteInvisibleChar
+		}
+
+		// strip control characters but keep line breaks and tabs, tabs are folded into spaces below
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			continue
+		}
+
+		if unicode.Is(unicode.Mn, r) {
+			combining++
+
+			if combining > rules.MaxCombiningMarks {
+				return "", ErrNoteCombiningMarks
+			}
+		} else {
+			combining = 0
+		}
+
+		builder.WriteRune(r)
+	}
+
+	note = builder.String()
+
+	if urlPattern.MatchString(note) {
+		switch rules.URLPolicy {
+		case urlPolicyReject:
+			return "", ErrNoteURL
+		case urlPolicyStrip:
+			note = urlPattern.ReplaceAllString(note, "")
+		}
+	}
+
+	if rules.Whitespace == "collapse" {
+		note = horizontalSpaces.ReplaceAllString(note, " ")
+		note = spacesAroundBreak.ReplaceAllString(note, "\n")
+		note = repeatedNewlines.ReplaceAllString(note, "\n\n")
+	}
+
+	note = strings.TrimSpace(note)
+
+	if note == "" {
+		return "", ErrNoteEmpty
+	}
+
+	if count := utf8.RuneCountInString(note); count > rules.MaxRunes {
+		return "", fmt.Errorf("%w of %d characters (got %d)", ErrNoteTooLong, rules.MaxRunes, count)
+	}
+
+	return note, nil
+}
+
+var ErrTagInvalid = errors.New("may only contain letters, digits, dashes and underscores")
+
+const maxTagRunes = 32
+
+// sanitizeTags lowercases and deduplicates free-form tags, a leading hash sign is dropped.
+func sanitizeTags(maxTags int, tags []string) ([]string, error) {
+	result := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(norm.NFC.String(tag)), "#"))
+
+		if tag == "" || slices.Contains(result, tag) {
+			continue
+		}
+
+		if utf8.RuneCountInString(tag) > maxTagRunes {
+			return nil, fmt.Errorf("%w of %d characters", ErrNoteTooLong, maxTagRunes)
+		}
+
+		for _, r := range tag {
+			if !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '-' && r != '_' {
+				return nil, ErrTagInvalid
+			}
+		}
+
+		result = append(result, tag)
+	}
+
+	if len(result) > maxTags {
+		return nil, fmt.Errorf("must not have more than %d tags", maxTags)
+	}
+
+	return result, nil
+}
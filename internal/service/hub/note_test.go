@@ -0,0 +1,118 @@
+package hub
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/brucexc/pray-to-earn/internal/config"
+)
+
+func TestSanitizeNote(t *testing.T) {
+	t.Parallel()
+
+	defaultRules := config.Note{
+		MaxRunes:          20,
+		MaxCombiningMarks: 2,
+		Normalization:     "nfc",
+		Whitespace:        "collapse",
+		URLPolicy:         "allow",
+	}
+
+	tests := []struct {
+		name    string
+		rules   func(rules *config.Note)
+		note    string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "plain",
+			note: "peace be with you",
+			want: "peace be with you",
+		},
+		{
+			name: "collapse whitespace",
+			note: "  peace \t be\n\n\n\nwith  you ",
+			want: "peace be\n\nwith you",
+		},
+		{
+			name:  "keep whitespace",
+			rules: func(rules *config.Note) { rules.Whitespace = "keep" },
+			note:  "peace  be",
+			want:  "peace  be",
+		},
+		{
+			name: "strip control characters",
+			note: "pea\x00ce\x07",
+			want: "peace",
+		},
+		{
+			name: "nfc normalization",
+			note: "cafe\u0301",
+			want: "caf\u00e9",
+		},
+		{
+			name:    "empty after trimming",
+			note:    " \n\t ",
+			wantErr: ErrNoteEmpty,
+		},
+		{
+			name:    "too many runes",
+			note:    strings.Repeat("a", 21),
+			wantErr: ErrNoteTooLong,
+		},
+		{
+			name:    "oversized payload",
+			note:    strings.Repeat("a", 20*4+1),
+			wantErr: ErrNoteTooLong,
+		},
+		{
+			name:    "bidi override",
+			note:    "amen\u202e",
+			wantErr: ErrNoteInvisibleChar,
+		},
+		{
+			name:    "zero width space",
+			note:    "a\u200bmen",
+			wantErr: ErrNoteInvisibleChar,
+		},
+		{
+			name:    "stacked combining marks",
+			note:    "a\u0300\u0301\u0302\u0303",
+			wantErr: ErrNoteCombiningMarks,
+		},
+		{
+			name:    "reject urls",
+			rules:   func(rules *config.Note) { rules.URLPolicy = "reject" },
+			note:    "see https://example.com",
+			wantErr: ErrNoteURL,
+		},
+		{
+			name:  "strip urls",
+			rules: func(rules *config.Note) { rules.URLPolicy = "strip" },
+			note:  "see www.example.com now",
+			want:  "see now",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rules := defaultRules
+			if tt.rules != nil {
+				tt.rules(&rules)
+			}
+
+			got, err := sanitizeNote(&rules, tt.note)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error: got %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("note: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
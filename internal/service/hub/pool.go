@@ -0,0 +1,162 @@
+package hub
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/internal/langdetect"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/text/language"
+)
+
+const (
+	// intersectionTTL is how long a combined category and language pool is cached.
+	intersectionTTL = time.Minute
+	// messagesCircle maps circle notes to their circle, so they can be dropped from the circle pool.
+	messagesCircle = "messages_circle"
+)
+
+// MessageFilter narrows random selection down to a part of the pool.
+type MessageFilter struct {
+	Category string
+	// Languages are tried in order before falling back to notes in any language.
+	Languages []string
+	// Circle picks from the notes of one private circle only, the other fields are ignored.
+	Circle string
+}
+
+func categoryPoolKey(category string) string {
+	return fmt.Sprintf("%s:category:%s", messagesSet, category)
+}
+
+func languagePoolKey(language string) string {
+	return fmt.Sprintf("%s:language:%s", messagesSet, language)
+}
+
+// intersectionPoolKey caches the notes of a category in one language.
+func intersectionPoolKey(category, language string) string {
+	return fmt.Sprintf("%s:language:%s", categoryPoolKey(category), language)
+}
+
+// circlePoolKey is kept apart from the public pool, circle notes are never in messages_set.
+func circlePoolKey(circle string) string {
+	return fmt.Sprintf("%s:circle:%s", messagesSet, circle)
+}
+
+// pool returns the redis set holding the candidates matching the category of the filter.
+func (f MessageFilter) pool() string {
+	if f.Category != "" {
+		return categoryPoolKey(f.Category)
+	}
+
+	return messagesSet
+}
+
+// pools returns the sets to pick from in order of preference, the last one ignores the language preference.
+// Combined category and language pools are cached for intersectionTTL and only rebuilt once they expired,
+// notes leaving the pools are removed from the cached ones as well.
+func (h *Hub) pools(ctx context.Context, filter MessageFilter) ([]string, error) {
+	if filter.Circle != "" {
+		return []string{circlePoolKey(filter.Circle)}, nil
+	}
+
+	pools := make([]string, 0, len(filter.Languages)+1)
+
+	if filter.Category == "" {
+		for _, preferred := range filter.Languages {
+			pools = append(pools, languagePoolKey(preferred))
+		}
+
+		return append(pools, filter.pool()), nil
+	}
+
+	for _, preferred := range filter.Languages {
+		pools = append(pools, intersectionPoolKey(filter.Category, preferred))
+	}
+
+	if len(pools) > 0 {
+		pipeline := h.redisClient.Pipeline()
+
+		exists := make([]*redis.IntCmd, 0, len(pools))
+		for _, key := range pools {
+			exists = append(exists, pipeline.Exists(ctx, key))
+		}
+
+		if _, err := pipeline.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("check intersected pools: %w", err)
+		}
+
+		pipeline = h.redisClient.TxPipeline()
+
+		for i, key := range pools {
+			if exists[i].Val() == 1 {
+				continue
+			}
+
+			pipeline.SInterStore(ctx, key, categoryPoolKey(filter.Category), languagePoolKey(filter.Languages[i]))
+			pipeline.Expire(ctx, key, intersectionTTL)
+		}
+
+		if pipeline.Len() > 0 {
+			if _, err := pipeline.Exec(ctx); err != nil {
+				return nil, fmt.Errorf("intersect pools: %w", err)
+			}
+		}
+	}
+
+	return append(pools, filter.pool()), nil
+}
+
+// preferredLanguages returns the explicitly requested language, or the supported ones from the Accept-Language header.
+func preferredLanguages(c echo.Context, explicit string) []string {
+	supported := langdetect.Languages()
+
+	if explicit != "" {
+		if slices.Contains(supported, explicit) {
+			return []string{explicit}
+		}
+
+		return nil
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(c.Request().Header.Get("Accept-Language"))
+	if err != nil {
+		return nil
+	}
+
+	var result []string
+
+	for _, tag := range tags {
+		base, _ := tag.Base()
+
+		if code := base.String(); slices.Contains(supported, code) && !slices.Contains(result, code) {
+			result = append(result, code)
+		}
+	}
+
+	return result
+}
+
+// validateCategory accepts an empty category or one from the configured list.
+func (h *Hub) validateCategory(category string) error {
+	if category == "" || slices.Contains(h.config.Category.Categories, category) {
+		return nil
+	}
+
+	return fmt.Errorf("must be one of %v", h.config.Category.Categories)
+}
+
+// peekPrice returns the amount to burn for a peek, asking for a category costs a premium.
+func (h *Hub) peekPrice(category string) *big.Int {
+	if category == "" {
+		return peekNodePrice
+	}
+
+	premium := new(big.Int).Mul(big.NewInt(1e18), big.NewInt(h.config.Category.PeekPremium))
+
+	return premium.Add(premium, peekNodePrice)
+}
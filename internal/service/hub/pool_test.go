@@ -0,0 +1,148 @@
+package hub
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestPools(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		filter MessageFilter
+		want   []string
+	}{
+		{
+			name:   "no filter",
+			filter: MessageFilter{},
+			want:   []string{messagesSet},
+		},
+		{
+			name:   "languages",
+			filter: MessageFilter{Languages: []string{"en", "de"}},
+			want:   []string{languagePoolKey("en"), languagePoolKey("de"), messagesSet},
+		},
+		{
+			name:   "category",
+			filter: MessageFilter{Category: "health"},
+			want:   []string{categoryPoolKey("health")},
+		},
+		{
+			name:   "category and language",
+			filter: MessageFilter{Category: "health", Languages: []string{"en"}},
+			want:   []string{categoryPoolKey("health") + ":language:en", categoryPoolKey("health")},
+		},
+		{
+			name:   "circle",
+			filter: MessageFilter{Circle: "c", Category: "health", Languages: []string{"en"}},
+			want:   []string{circlePoolKey("c")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hub, _ := newTestHub(t)
+
+			got, err := hub.pools(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPoolsIntersectionCache(t *testing.T) {
+	t.Parallel()
+
+	hub, server := newTestHub(t)
+	ctx := context.Background()
+	filter := MessageFilter{Category: "health", Languages: []string{"en"}}
+	key := categoryPoolKey("health") + ":language:en"
+
+	server.SAdd(categoryPoolKey("health"), "a", "b")
+	server.SAdd(languagePoolKey("en"), "a", "c")
+
+	steps := []struct {
+		name string
+		// before runs ahead of the lookup
+		before func()
+		want   []string
+	}{
+		{
+			name: "built on first use",
+			want: []string{"a"},
+		},
+		{
+			name: "reused while cached",
+			before: func() {
+				server.SAdd(categoryPoolKey("health"), "c")
+			},
+			want: []string{"a"},
+		},
+		{
+			name: "rebuilt after expiry",
+			before: func() {
+				server.FastForward(intersectionTTL)
+			},
+			want: []string{"a", "c"},
+		},
+	}
+
+	for _, step := range steps {
+		if step.before != nil {
+			step.before()
+		}
+
+		if _, err := hub.pools(ctx, filter); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		members, err := server.Members(key)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		if !slices.Equal(members, step.want) {
+			t.Errorf("%s: got %v, want %v", step.name, members, step.want)
+		}
+	}
+}
+
+func TestRemoveFromPools(t *testing.T) {
+	t.Parallel()
+
+	hub, server := newTestHub(t)
+	ctx := context.Background()
+
+	server.SAdd(messagesSet, "a", "b")
+	server.SAdd(categoryPoolKey("health"), "a", "b")
+	server.SAdd(languagePoolKey("en"), "a", "b")
+
+	// build the cached intersection before the note leaves
+	if _, err := hub.pools(ctx, MessageFilter{Category: "health", Languages: []string{"en"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := hub.removeFromPools(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{messagesSet, categoryPoolKey("health"), languagePoolKey("en"), intersectionPoolKey("health", "en")} {
+		members, err := server.Members(key)
+		if err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+
+		if !slices.Equal(members, []string{"b"}) {
+			t.Errorf("%s: got %v, want [b]", key, members)
+		}
+	}
+}
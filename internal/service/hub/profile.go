@@ -0,0 +1,275 @@
+package hub
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/internal/database"
+	"github.com/brucexc/pray-to-earn/internal/service/hub/model/errorx"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// avatarPath is the route uploaded avatars are served under.
+const avatarPath = "/pray/avatars"
+
+var (
+	ErrDisplayNameReserved = errors.New("display name must not look like an address or a pseudonym")
+	ErrAvatarUnknown       = errors.New("avatar is not one of the presets")
+	ErrAvatarTooLarge      = errors.New("avatar image is too large")
+	ErrAvatarType          = errors.New("avatar must be a png, jpeg, gif or webp image")
+)
+
+// avatarTypes maps the accepted image types to the extension they are stored with.
+var avatarTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+type GetProfileRequest struct {
+	Address common.Address `param:"address" validate:"required"`
+}
+
+// UpdateProfileRequest replaces the whole profile, it is sent as json or as a multipart form carrying an avatar_file upload.
+type UpdateProfileRequest struct {
+	Address     common.Address `param:"address" validate:"required"`
+	DisplayName string         `json:"display_name" form:"display_name"`
+	Bio         string         `json:"bio" form:"bio"`
+	// Avatar is one of the configured presets, it is ignored when an image is uploaded.
+	Avatar string `json:"avatar" form:"avatar"`
+}
+
+func (h *Hub) GetProfile(c echo.Context) error {
+	var request GetProfileRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	profile, err := h.databaseClient.FindProfile(c.Request().Context(), request.Address)
+	if err != nil {
+		if errors.Is(err, database.ErrorRowNotFound) {
+			return errorx.NotFoundError(c, fmt.Errorf("profile %s not found", request.Address.Hex()))
+		}
+
+		zap.L().Error("failed to find profile", zap.String("address", request.Address.Hex()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	return c.JSON(http.StatusOK, Response{
+		Data: profile,
+	})
+}
+
+func (h *Hub) UpdateProfile(c echo.Context) error {
+	var request UpdateProfileRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	if address != request.Address {
+		return errorx.ForbiddenError(c, fmt.Errorf("a profile can only be updated by its own wallet"))
+	}
+
+	profile := schema.Profile{
+		Address: request.Address,
+		Avatar:  request.Avatar,
+	}
+
+	if profile.DisplayName, err = h.sanitizeDisplayName(request.DisplayName); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("display_name: %w", err))
+	}
+
+	if request.Bio != "" {
+		rules := *h.config.Note
+		rules.MaxRunes = h.config.Profile.MaxBio
+
+		if profile.Bio, err = sanitizeNote(&rules, request.Bio); err != nil {
+			return errorx.ValidationFailedError(c, fmt.Errorf("bio: %w", err))
+		}
+	}
+
+	if file, err := c.FormFile("avatar_file"); err == nil {
+		if profile.Avatar, err = h.saveAvatar(file); err != nil {
+			if errors.Is(err, ErrAvatarTooLarge) || errors.Is(err, ErrAvatarType) {
+				return errorx.ValidationFailedError(c, fmt.Errorf("avatar_file: %w", err))
+			}
+
+			zap.L().Error("failed to save avatar", zap.String("address", address.Hex()), zap.Error(err))
+
+			return errorx.InternalError(c)
+		}
+	} else if !errors.Is(err, http.ErrMissingFile) && !errors.Is(err, http.ErrNotMultipart) {
+		return errorx.BadParamsError(c, fmt.Errorf("avatar_file: %w", err))
+	} else if profile.Avatar != "" && !slices.Contains(h.config.Profile.Avatars, profile.Avatar) {
+		return errorx.ValidationFailedError(c, fmt.Errorf("avatar: %w", ErrAvatarUnknown))
+	}
+
+	if err := h.databaseClient.SaveProfile(c.Request().Context(), &profile); err != nil {
+		zap.L().Error("failed to save profile", zap.String("address", address.Hex()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	zap.L().Info("updated profile", zap.String("address", address.Hex()), zap.String("display_name", profile.DisplayName))
+
+	return c.JSON(http.StatusOK, Response{
+		Data: profile,
+	})
+}
+
+// sanitizeDisplayName applies the note rules to a display name, names that could pass for another author are rejected.
+func (h *Hub) sanitizeDisplayName(name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+
+	rules := *h.config.Note
+	rules.MaxRunes = h.config.Profile.MaxDisplayName
+	rules.URLPolicy = "reject"
+
+	name, err := sanitizeNote(&rules, name)
+	if err != nil {
+		return "", err
+	}
+
+	lower := strings.ToLower(name)
+	if strings.HasPrefix(lower, "0x") || strings.HasPrefix(lower, pseudonymPrefix) {
+		return "", ErrDisplayNameReserved
+	}
+
+	return name, nil
+}
+
+// saveAvatar stores an uploaded image under its content hash and returns the path it is served from.
+func (h *Hub) saveAvatar(file *multipart.FileHeader) (string, error) {
+	if file.Size > h.config.Profile.MaxAvatarSize {
+		return "", ErrAvatarTooLarge
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return "", fmt.Errorf("open upload: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, h.config.Profile.MaxAvatarSize+1))
+	if err != nil {
+		return "", fmt.Errorf("read upload: %w", err)
+	}
+
+	if int64(len(data)) > h.config.Profile.MaxAvatarSize {
+		return "", ErrAvatarTooLarge
+	}
+
+	// the declared content type is not trusted, the image type is sniffed from the data
+	extension, ok := avatarTypes[http.DetectContentType(data)]
+	if !ok {
+		return "", ErrAvatarType
+	}
+
+	hash := sha256.Sum256(data)
+	name := hex.EncodeToString(hash[:16]) + extension
+
+	if err := os.MkdirAll(h.config.Profile.AvatarDir, 0o755); err != nil {
+		return "", fmt.Errorf("create avatar directory: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(h.config.Profile.AvatarDir, name), data, 0o644); err != nil {
+		return "", fmt.Errorf("write avatar: %w", err)
+	}
+
+	return avatarPath + "/" + name, nil
+}
+
+// displayNames returns the display names of the addresses that have set one.
+func (h *Hub) displayNames(ctx context.Context, addresses ...common.Address) (map[common.Address]string, error) {
+	profiles, err := h.databaseClient.FindProfiles(ctx, addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[common.Address]string, len(profiles))
+	for address, profile := range profiles {
+		if profile.DisplayName != "" {
+			names[address] = profile.DisplayName
+		}
+	}
+
+	return names, nil
+}
+
+// renderMessage renders a served note and its replies from the note store with the current display names,
+// notes written before the note store existed are served as stored in redis.
+func (h *Hub) renderMessage(ctx context.Context, message *Message) error {
+	note, err := h.databaseClient.FindNote(ctx, message.ID, nil)
+	if err != nil {
+		if errors.Is(err, database.ErrorRowNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("find note: %w", err)
+	}
+
+	var replies []*schema.Reply
+
+	if len(message.Replies) > 0 {
+		if replies, err = h.databaseClient.FindReplies(ctx, schema.ReplyQuery{
+			NoteID: message.ID,
+			Limit:  len(message.Replies),
+		}); err != nil {
+			return fmt.Errorf("find replies: %w", err)
+		}
+	}
+
+	addresses := []common.Address{note.Address}
+	for _, reply := range replies {
+		addresses = append(addresses, reply.Address)
+	}
+
+	names, err := h.displayNames(ctx, addresses...)
+	if err != nil {
+		return fmt.Errorf("find display names: %w", err)
+	}
+
+	message.Content = renderNote(time.Unix(note.CreatedAt, 0), h.authorLabel(names, note.Address, note.MessageID, note.Anonymous, false), note.Note)
+
+	// replies only line up with the stored ones when all of them made it into the note store
+	if len(replies) == len(message.Replies) {
+		for i, reply := range replies {
+			message.Replies[i] = renderNote(time.Unix(reply.CreatedAt, 0), h.authorLabel(names, reply.Address, note.MessageID, reply.Anonymous, false), reply.Reply)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,394 @@
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/brucexc/pray-to-earn/internal/database"
+	"github.com/brucexc/pray-to-earn/internal/service/hub/model/errorx"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/creasty/defaults"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type GetNoteRequest struct {
+	ID string `param:"id" validate:"required"`
+}
+
+type GetNotesRequest struct {
+	Author common.Address `query:"author" validate:"required"`
+	Cursor string         `query:"cursor"`
+	Limit  int            `query:"limit" validate:"min=1,max=100" default:"20"`
+}
+
+type GetNoteRepliesRequest struct {
+	ID     string `param:"id" validate:"required"`
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit" validate:"min=1,max=100" default:"20"`
+}
+
+type SearchNotesRequest struct {
+	Query  string `query:"q" validate:"required,max=200"`
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit" validate:"min=1,max=100" default:"20"`
+}
+
+type SearchResultView struct {
+	NoteView
+	Rank float64 `json:"rank"`
+}
+
+type NoteView struct {
+	ID            string   `json:"id"`
+	Author        string   `json:"author"`
+	Note          string   `json:"note"`
+	Category      string   `json:"category,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+	Language      string   `json:"language,omitempty"`
+	RevealAt      int64    `json:"reveal_at,omitempty"`
+	ExpiresAt     int64    `json:"expires_at,omitempty"`
+	ArchivedAt    int64    `json:"archived_at,omitempty"`
+	EditedAt      int64    `json:"edited_at,omitempty"`
+	AnsweredAt    int64    `json:"answered_at,omitempty"`
+	Testimony     string   `json:"testimony,omitempty"`
+	Intercessions int64    `json:"intercessions"`
+	CreatedAt     int64    `json:"created_at"`
+}
+
+type ReplyView struct {
+	ID        uint64 `json:"id"`
+	Author    string `json:"author"`
+	Reply     string `json:"reply"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+func (h *Hub) GetNote(c echo.Context) error {
+	var request GetNoteRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	viewer, err := optionalSigner(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	note, err := h.databaseClient.FindNote(c.Request().Context(), request.ID, viewer)
+	if err != nil {
+		if errors.Is(err, database.ErrorRowNotFound) {
+			return errorx.NotFoundError(c, fmt.Errorf("note %s not found", request.ID))
+		}
+
+		zap.L().Error("failed to find note", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	readable, err := h.canRead(c.Request().Context(), note, viewer)
+	if err != nil {
+		zap.L().Error("failed to check circle membership", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	// circle notes are reported missing to outsiders rather than forbidden, so their existence does not leak
+	if !readable {
+		return errorx.NotFoundError(c, fmt.Errorf("note %s not found", request.ID))
+	}
+
+	names, err := h.displayNames(c.Request().Context(), note.Address)
+	if err != nil {
+		zap.L().Error("failed to find display names", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	return c.JSON(http.StatusOK, Response{
+		Data: h.newNoteView(note, viewer, names),
+	})
+}
+
+func (h *Hub) GetNotes(c echo.Context) error {
+	var request GetNotesRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := defaults.Set(&request); err != nil {
+		zap.L().Error("set default values for request", zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	// notes are only listed to their own author, otherwise the list links every prayer to a wallet
+	address, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	if address != request.Author {
+		return errorx.ForbiddenError(c, fmt.Errorf("notes can only be listed by their author"))
+	}
+
+	cursor, err := parseCursor(request.Cursor)
+	if err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("cursor: %w", err))
+	}
+
+	notes, err := h.databaseClient.FindNotes(c.Request().Context(), schema.NoteQuery{
+		Address: &request.Author,
+		Cursor:  cursor,
+		Limit:   request.Limit,
+	})
+	if err != nil {
+		zap.L().Error("failed to find notes", zap.String("author", request.Author.Hex()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	views := make([]NoteView, 0, len(notes))
+	for _, note := range notes {
+		views = append(views, h.newNoteView(note, &address, nil))
+	}
+
+	var nextCursor string
+	if len(notes) == request.Limit {
+		nextCursor = strconv.FormatUint(notes[len(notes)-1].ID, 10)
+	}
+
+	return c.JSON(http.StatusOK, Response{
+		Data:   views,
+		Cursor: nextCursor,
+	})
+}
+
+func (h *Hub) GetNoteReplies(c echo.Context) error {
+	var request GetNoteRepliesRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := defaults.Set(&request); err != nil {
+		zap.L().Error("set default values for request", zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	viewer, err := optionalSigner(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	cursor, err := parseCursor(request.Cursor)
+	if err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("cursor: %w", err))
+	}
+
+	note, err := h.databaseClient.FindNote(c.Request().Context(), request.ID, viewer)
+	if err != nil {
+		if errors.Is(err, database.ErrorRowNotFound) {
+			return errorx.NotFoundError(c, fmt.Errorf("note %s not found", request.ID))
+		}
+
+		zap.L().Error("failed to find note", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	readable, err := h.canRead(c.Request().Context(), note, viewer)
+	if err != nil {
+		zap.L().Error("failed to check circle membership", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if !readable {
+		return errorx.NotFoundError(c, fmt.Errorf("note %s not found", request.ID))
+	}
+
+	replies, err := h.databaseClient.FindReplies(c.Request().Context(), schema.ReplyQuery{
+		NoteID: request.ID,
+		Cursor: cursor,
+		Limit:  request.Limit,
+	})
+	if err != nil {
+		zap.L().Error("failed to find replies", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	repliers := make([]common.Address, 0, len(replies))
+	for _, reply := range replies {
+		repliers = append(repliers, reply.Address)
+	}
+
+	names, err := h.displayNames(c.Request().Context(), repliers...)
+	if err != nil {
+		zap.L().Error("failed to find display names", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	// the note author sees who replied, everyone else only sees the usual address prefix,
+	// anonymous replies are only disclosed to the replier themself
+	isAuthor := viewer != nil && *viewer == note.Address
+
+	views := make([]ReplyView, 0, len(replies))
+	for _, reply := range replies {
+		isReplier := viewer != nil && *viewer == reply.Address
+
+		views = append(views, ReplyView{
+			ID:        reply.ID,
+			Author:    h.authorLabel(names, reply.Address, note.MessageID, reply.Anonymous, isReplier || (isAuthor && !reply.Anonymous)),
+			Reply:     reply.Reply,
+			CreatedAt: reply.CreatedAt,
+		})
+	}
+
+	var nextCursor string
+	if len(replies) == request.Limit {
+		nextCursor = strconv.FormatUint(replies[len(replies)-1].ID, 10)
+	}
+
+	return c.JSON(http.StatusOK, Response{
+		Data:   views,
+		Cursor: nextCursor,
+	})
+}
+
+func (h *Hub) SearchNotes(c echo.Context) error {
+	var request SearchNotesRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := defaults.Set(&request); err != nil {
+		zap.L().Error("set default values for request", zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	// search results are ranked, so the cursor is an offset rather than a note id
+	offset, err := parseCursor(request.Cursor)
+	if err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("cursor: %w", err))
+	}
+
+	query := schema.NoteSearchQuery{
+		Query: request.Query,
+		// a query must use the configuration the notes were indexed with, or stemming would not match
+		Language: h.config.Search.Language,
+		Limit:    request.Limit,
+	}
+
+	if offset != nil {
+		query.Offset = int(*offset)
+	}
+
+	results, err := h.databaseClient.SearchNotes(c.Request().Context(), query)
+	if err != nil {
+		zap.L().Error("failed to search notes", zap.String("query", request.Query), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	authors := make([]common.Address, 0, len(results))
+	for _, result := range results {
+		authors = append(authors, result.Note.Address)
+	}
+
+	names, err := h.displayNames(c.Request().Context(), authors...)
+	if err != nil {
+		zap.L().Error("failed to find display names", zap.String("query", request.Query), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	views := make([]SearchResultView, 0, len(results))
+	for _, result := range results {
+		views = append(views, SearchResultView{
+			NoteView: h.newNoteView(result.Note, nil, names),
+			Rank:     result.Rank,
+		})
+	}
+
+	var nextCursor string
+	if len(results) == request.Limit {
+		nextCursor = strconv.Itoa(query.Offset + len(results))
+	}
+
+	return c.JSON(http.StatusOK, Response{
+		Data:   views,
+		Cursor: nextCursor,
+	})
+}
+
+// optionalSigner returns the signing wallet, or nil for unsigned requests.
+func optionalSigner(c echo.Context) (*common.Address, error) {
+	address, err := signer(c)
+	if err != nil {
+		if errors.Is(err, ErrSignatureRequired) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &address, nil
+}
+
+func (h *Hub) newNoteView(note *schema.Note, viewer *common.Address, names map[common.Address]string) NoteView {
+	return NoteView{
+		ID:            note.MessageID,
+		Author:        h.authorLabel(names, note.Address, note.MessageID, note.Anonymous, viewer != nil && *viewer == note.Address),
+		Note:          note.Note,
+		Category:      note.Category,
+		Tags:          note.Tags,
+		Language:      note.Language,
+		RevealAt:      note.RevealAt,
+		ExpiresAt:     note.ExpiresAt,
+		ArchivedAt:    note.ArchivedAt,
+		EditedAt:      note.EditedAt,
+		AnsweredAt:    note.AnsweredAt,
+		Testimony:     note.Testimony,
+		Intercessions: note.Intercessions,
+		CreatedAt:     note.CreatedAt,
+	}
+}
+
+func parseCursor(cursor string) (*uint64, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseUint(cursor, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor %s", cursor)
+	}
+
+	return &value, nil
+}
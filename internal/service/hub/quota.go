@@ -0,0 +1,56 @@
+package hub
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	quotaScopeCircle       = "circle"
+	quotaScopeIntercession = "intercession"
+	quotaScopeGift         = "gift"
+	quotaScopeFaucet       = "faucet"
+	quotaScopeAnswer       = "answer"
+	quotaScopeChain        = "chain"
+	// quotaScopeGiftGlobal is the gift budget of the whole server, kept apart from the budgets of senders.
+	quotaScopeGiftGlobal = "gift_global"
+
+	// quotaTTL keeps a day's counter around a little longer than the day itself.
+	quotaTTL = 48 * time.Hour
+)
+
+// consumeQuota counts one use of a daily quota and reports whether it is still within the limit, days are in UTC.
+func (h *Hub) consumeQuota(ctx context.Context, scope string, address common.Address, limit int64) (bool, error) {
+	return h.consumeQuotaBy(ctx, scope, address, 1, limit)
+}
+
+// consumeQuotaBy adds amount to a daily quota, an amount that does not fit is handed back and reported as false.
+func (h *Hub) consumeQuotaBy(ctx context.Context, scope string, address common.Address, amount, limit int64) (bool, error) {
+	key := quotaKey(scope, address)
+
+	pipeline := h.redisClient.TxPipeline()
+	used := pipeline.IncrBy(ctx, key, amount)
+	pipeline.Expire(ctx, key, quotaTTL)
+
+	if _, err := pipeline.Exec(ctx); err != nil {
+		return false, err
+	}
+
+	if used.Val() <= limit {
+		return true, nil
+	}
+
+	return false, h.redisClient.DecrBy(ctx, key, amount).Err()
+}
+
+// refundQuota hands back an amount consumed earlier the same day, when the action it paid for did not happen.
+func (h *Hub) refundQuota(ctx context.Context, scope string, address common.Address, amount int64) error {
+	return h.redisClient.DecrBy(ctx, quotaKey(scope, address), amount).Err()
+}
+
+func quotaKey(scope string, address common.Address) string {
+	return fmt.Sprintf("quota:%s:%s:%s", scope, address.Hex(), time.Now().UTC().Format("20060102"))
+}
@@ -0,0 +1,106 @@
+package hub
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestConsumeQuota(t *testing.T) {
+	t.Parallel()
+
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	type step struct {
+		consume int64
+		refund  int64
+		want    bool
+		used    int64
+	}
+
+	tests := []struct {
+		name  string
+		limit int64
+		steps []step
+	}{
+		{
+			name:  "within limit",
+			limit: 2,
+			steps: []step{
+				{consume: 1, want: true, used: 1},
+				{consume: 1, want: true, used: 2},
+			},
+		},
+		{
+			name:  "over limit is handed back",
+			limit: 2,
+			steps: []step{
+				{consume: 1, want: true, used: 1},
+				{consume: 1, want: true, used: 2},
+				{consume: 1, want: false, used: 2},
+			},
+		},
+		{
+			name:  "amount that does not fit",
+			limit: 5,
+			steps: []step{
+				{consume: 3, want: true, used: 3},
+				{consume: 3, want: false, used: 3},
+				{consume: 2, want: true, used: 5},
+			},
+		},
+		{
+			name:  "refund frees the quota",
+			limit: 1,
+			steps: []step{
+				{consume: 1, want: true, used: 1},
+				{refund: 1, used: 0},
+				{consume: 1, want: true, used: 1},
+			},
+		},
+		{
+			name:  "zero limit",
+			limit: 0,
+			steps: []step{
+				{consume: 1, want: false, used: 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hub, server := newTestHub(t)
+			ctx := context.Background()
+
+			for i, step := range tt.steps {
+				if step.refund > 0 {
+					if err := hub.refundQuota(ctx, quotaScopeGift, address, step.refund); err != nil {
+						t.Fatalf("step %d: %v", i, err)
+					}
+				} else {
+					got, err := hub.consumeQuotaBy(ctx, quotaScopeGift, address, step.consume, tt.limit)
+					if err != nil {
+						t.Fatalf("step %d: %v", i, err)
+					}
+
+					if got != step.want {
+						t.Errorf("step %d: got %t, want %t", i, got, step.want)
+					}
+				}
+
+				value, _ := server.Get(quotaKey(quotaScopeGift, address))
+				if used, _ := strconv.ParseInt(value, 10, 64); used != step.used {
+					t.Errorf("step %d: used %d, want %d", i, used, step.used)
+				}
+			}
+
+			if ttl := server.TTL(quotaKey(quotaScopeGift, address)); ttl <= 0 || ttl > quotaTTL {
+				t.Errorf("ttl: got %s, want up to %s", ttl, quotaTTL)
+			}
+		})
+	}
+}
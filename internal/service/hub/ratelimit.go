@@ -0,0 +1,260 @@
+package hub
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/internal/config"
+	"github.com/brucexc/pray-to-earn/internal/service/hub/model/errorx"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	RateLimitKeyIP      = "ip"
+	RateLimitKeyAddress = "address"
+	RateLimitKeyAPIKey  = "api_key"
+
+	RateLimitAlgorithmSlidingWindow = "sliding_window"
+	RateLimitAlgorithmTokenBucket   = "token_bucket"
+
+	HeaderAPIKey             = "X-Pray-API-Key"
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+)
+
+// slidingWindowScript keeps the request times of the window in a sorted set.
+// It returns whether the request is allowed, the remaining requests and the milliseconds until the oldest request leaves the window.
+var slidingWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], 0, now - window)
+
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+
+redis.call('PEXPIRE', KEYS[1], window)
+
+local reset = 0
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+
+return {allowed, limit - count, reset}
+`)
+
+// tokenBucketScript refills limit tokens evenly over the window and takes one per request.
+// It returns whether the request is allowed, the whole tokens left and the milliseconds until the next token and a full bucket.
+var tokenBucketScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+local rate = limit / window
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated_at')
+local tokens = tonumber(state[1]) or limit
+local updated_at = tonumber(state[2]) or now
+
+tokens = math.min(limit, tokens + (now - updated_at) * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated_at', now)
+redis.call('PEXPIRE', KEYS[1], window)
+
+return {allowed, math.floor(tokens), retry, math.ceil((limit - tokens) / rate)}
+`)
+
+type rateLimitResult struct {
+	allowed    bool
+	limit      int64
+	remaining  int64
+	retryAfter time.Duration
+	reset      time.Duration
+}
+
+// RateLimit applies the rules configured for the matched route, keyed on the client IP, the signing address and the API key.
+// A request passes only if every rule allows it, the tightest rule is reported in the RateLimit-* headers.
+// Limiting fails open, a redis error is logged and the request goes through.
+func (h *Hub) RateLimit(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		route := c.Request().Method + " " + c.Path()
+
+		rules := h.config.RateLimit.Routes[route]
+		if len(rules) == 0 {
+			return next(c)
+		}
+
+		subjects := rateLimitSubjects(c)
+
+		if rateLimitAllowlisted(h.config.RateLimit.Allowlist, subjects) {
+			return next(c)
+		}
+
+		var tightest *rateLimitResult
+
+		for _, rule := range rules {
+			subject := subjects[rule.Key]
+			if subject == "" {
+				continue
+			}
+
+			result, err := h.takeRateLimit(c.Request().Context(), route, rule, subject)
+			if err != nil {
+				zap.L().Error("failed to apply rate limit", zap.String("route", route), zap.String("key", rule.Key), zap.Error(err))
+
+				continue
+			}
+
+			if tightest == nil || tighterRateLimit(result, tightest) {
+				tightest = result
+			}
+		}
+
+		if tightest == nil {
+			return next(c)
+		}
+
+		headers := c.Response().Header()
+		headers.Set(HeaderRateLimitLimit, strconv.FormatInt(tightest.limit, 10))
+		headers.Set(HeaderRateLimitRemaining, strconv.FormatInt(tightest.remaining, 10))
+		headers.Set(HeaderRateLimitReset, strconv.FormatInt(ceilSeconds(tightest.reset), 10))
+
+		if !tightest.allowed {
+			headers.Set(echo.HeaderRetryAfter, strconv.FormatInt(ceilSeconds(tightest.retryAfter), 10))
+
+			return errorx.TooManyRequestError(c, fmt.Errorf("too many requests"))
+		}
+
+		return next(c)
+	}
+}
+
+func (h *Hub) takeRateLimit(ctx context.Context, route string, rule config.RateLimitRule, subject string) (*rateLimitResult, error) {
+	key := fmt.Sprintf("ratelimit:%s:%s:%s:%s", rule.Algorithm, route, rule.Key, subject)
+	now := time.Now().UnixMilli()
+	window := rule.Window.Milliseconds()
+
+	var (
+		values []int64
+		err    error
+	)
+
+	switch rule.Algorithm {
+	case RateLimitAlgorithmSlidingWindow:
+		member := fmt.Sprintf("%d-%d", now, rand.Int63())
+		values, err = slidingWindowScript.Run(ctx, h.redisClient, []string{key}, now, window, rule.Limit, member).Int64Slice()
+	case RateLimitAlgorithmTokenBucket:
+		values, err = tokenBucketScript.Run(ctx, h.redisClient, []string{key}, now, window, rule.Limit).Int64Slice()
+	default:
+		return nil, fmt.Errorf("unsupported rate limit algorithm %s", rule.Algorithm)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := rateLimitResult{
+		allowed:   values[0] == 1,
+		limit:     rule.Limit,
+		remaining: values[1],
+	}
+
+	if rule.Algorithm == RateLimitAlgorithmSlidingWindow {
+		result.reset = time.Duration(values[2]) * time.Millisecond
+		if !result.allowed {
+			result.retryAfter = result.reset
+		}
+	} else {
+		result.retryAfter = time.Duration(values[2]) * time.Millisecond
+		result.reset = time.Duration(values[3]) * time.Millisecond
+	}
+
+	return &result, nil
+}
+
+// tighterRateLimit prefers a denial with the longest wait, then the fewest requests left.
+func tighterRateLimit(a, b *rateLimitResult) bool {
+	if a.allowed != b.allowed {
+		return !a.allowed
+	}
+
+	if !a.allowed {
+		return a.retryAfter > b.retryAfter
+	}
+
+	return a.remaining < b.remaining
+}
+
+// rateLimitSubjects returns the value of every key dimension present on the request.
+// The address is only taken from a verified signature, an unsigned address could be rotated at will to dodge its limit.
+func rateLimitSubjects(c echo.Context) map[string]string {
+	subjects := map[string]string{
+		RateLimitKeyIP: c.RealIP(),
+	}
+
+	if apiKey := c.Request().Header.Get(HeaderAPIKey); apiKey != "" {
+		// keep the key itself out of redis
+		digest := sha256.Sum256([]byte(apiKey))
+		subjects[RateLimitKeyAPIKey] = hex.EncodeToString(digest[:16])
+	}
+
+	if address, err := signer(c); err == nil {
+		subjects[RateLimitKeyAddress] = address.Hex()
+	}
+
+	return subjects
+}
+
+// rateLimitAllowlisted matches the subjects against allowlist entries of IPs, CIDR ranges and API keys.
+func rateLimitAllowlisted(allowlist []string, subjects map[string]string) bool {
+	ip := net.ParseIP(subjects[RateLimitKeyIP])
+
+	for _, entry := range allowlist {
+		switch {
+		case strings.Contains(entry, "/"):
+			if _, network, err := net.ParseCIDR(entry); err == nil && ip != nil && network.Contains(ip) {
+				return true
+			}
+		case net.ParseIP(entry) != nil:
+			if ip != nil && net.ParseIP(entry).Equal(ip) {
+				return true
+			}
+		default:
+			if digest := sha256.Sum256([]byte(entry)); subjects[RateLimitKeyAPIKey] == hex.EncodeToString(digest[:16]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func ceilSeconds(duration time.Duration) int64 {
+	return int64(math.Ceil(duration.Seconds()))
+}
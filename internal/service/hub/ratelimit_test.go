@@ -0,0 +1,262 @@
+package hub
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/internal/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+)
+
+func TestTakeRateLimit(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		allowed   bool
+		remaining int64
+		// retryAfter is checked to be within a second below this
+		retryAfter time.Duration
+	}
+
+	tests := []struct {
+		name string
+		rule config.RateLimitRule
+		want []want
+	}{
+		{
+			name: "sliding window",
+			rule: config.RateLimitRule{Key: RateLimitKeyIP, Algorithm: RateLimitAlgorithmSlidingWindow, Limit: 2, Window: time.Minute},
+			want: []want{
+				{allowed: true, remaining: 1},
+				{allowed: true, remaining: 0},
+				{allowed: false, remaining: 0, retryAfter: time.Minute},
+			},
+		},
+		{
+			name: "token bucket",
+			rule: config.RateLimitRule{Key: RateLimitKeyIP, Algorithm: RateLimitAlgorithmTokenBucket, Limit: 2, Window: time.Minute},
+			want: []want{
+				{allowed: true, remaining: 1},
+				{allowed: true, remaining: 0},
+				// one token refills every 30 seconds
+				{allowed: false, remaining: 0, retryAfter: 30 * time.Second},
+			},
+		},
+		{
+			name: "single request window",
+			rule: config.RateLimitRule{Key: RateLimitKeyIP, Algorithm: RateLimitAlgorithmSlidingWindow, Limit: 1, Window: 5 * time.Second},
+			want: []want{
+				{allowed: true, remaining: 0},
+				{allowed: false, remaining: 0, retryAfter: 5 * time.Second},
+				{allowed: false, remaining: 0, retryAfter: 5 * time.Second},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hub, _ := newTestHub(t)
+
+			for i, want := range tt.want {
+				result, err := hub.takeRateLimit(context.Background(), "POST /pray/knock", tt.rule, "192.0.2.1")
+				if err != nil {
+					t.Fatalf("request %d: %v", i, err)
+				}
+
+				if result.allowed != want.allowed || result.remaining != want.remaining {
+					t.Errorf("request %d: got allowed %t remaining %d, want allowed %t remaining %d", i, result.allowed, result.remaining, want.allowed, want.remaining)
+				}
+
+				if want.allowed && result.retryAfter != 0 {
+					t.Errorf("request %d: got retry after %s for an allowed request", i, result.retryAfter)
+				}
+
+				if !want.allowed && (result.retryAfter > want.retryAfter || result.retryAfter < want.retryAfter-time.Second) {
+					t.Errorf("request %d: got retry after %s, want about %s", i, result.retryAfter, want.retryAfter)
+				}
+
+				if result.reset <= 0 || result.reset > tt.rule.Window {
+					t.Errorf("request %d: got reset %s, want within the window", i, result.reset)
+				}
+			}
+		})
+	}
+}
+
+func TestTakeRateLimitSubjectsAreSeparate(t *testing.T) {
+	t.Parallel()
+
+	hub, _ := newTestHub(t)
+	rule := config.RateLimitRule{Key: RateLimitKeyIP, Algorithm: RateLimitAlgorithmSlidingWindow, Limit: 1, Window: time.Minute}
+
+	for _, subject := range []string{"192.0.2.1", "192.0.2.2"} {
+		result, err := hub.takeRateLimit(context.Background(), "POST /pray/knock", rule, subject)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !result.allowed {
+			t.Errorf("%s: got denied, want its own limit", subject)
+		}
+	}
+}
+
+func TestRateLimitSubjects(t *testing.T) {
+	t.Parallel()
+
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	tests := []struct {
+		name        string
+		signed      bool
+		apiKey      string
+		wantAddress string
+		wantAPIKey  bool
+	}{
+		{
+			name: "unsigned request has no address",
+		},
+		{
+			name:        "signed request",
+			signed:      true,
+			wantAddress: address.Hex(),
+		},
+		{
+			name:       "api key",
+			apiKey:     "key",
+			wantAPIKey: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			request := httptest.NewRequest(http.MethodPost, "/pray/knock", nil)
+			request.RemoteAddr = "192.0.2.1:1234"
+			// an address header without a signature must not count
+			request.Header.Set(HeaderAddress, "0x0000000000000000000000000000000000000002")
+
+			if tt.apiKey != "" {
+				request.Header.Set(HeaderAPIKey, tt.apiKey)
+			}
+
+			c := echo.New().NewContext(request, httptest.NewRecorder())
+			if tt.signed {
+				c.Set(contextKeySigner, &verifiedSigner{address: address})
+			}
+
+			subjects := rateLimitSubjects(c)
+
+			if subjects[RateLimitKeyIP] != "192.0.2.1" {
+				t.Errorf("ip: got %q, want 192.0.2.1", subjects[RateLimitKeyIP])
+			}
+
+			if subjects[RateLimitKeyAddress] != tt.wantAddress {
+				t.Errorf("address: got %q, want %q", subjects[RateLimitKeyAddress], tt.wantAddress)
+			}
+
+			// the digest is kept rather than the key itself
+			if apiKey := subjects[RateLimitKeyAPIKey]; (apiKey != "") != tt.wantAPIKey || (apiKey != "" && apiKey == tt.apiKey) {
+				t.Errorf("api key: got %q, want a digest %t", apiKey, tt.wantAPIKey)
+			}
+		})
+	}
+}
+
+func TestRateLimitAllowlisted(t *testing.T) {
+	t.Parallel()
+
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	subjects := func(ip, apiKey string) map[string]string {
+		request := httptest.NewRequest(http.MethodPost, "/pray/knock", nil)
+		request.RemoteAddr = ip + ":1234"
+
+		if apiKey != "" {
+			request.Header.Set(HeaderAPIKey, apiKey)
+		}
+
+		c := echo.New().NewContext(request, httptest.NewRecorder())
+		c.Set(contextKeySigner, &verifiedSigner{address: address})
+
+		return rateLimitSubjects(c)
+	}
+
+	tests := []struct {
+		name      string
+		allowlist []string
+		subjects  map[string]string
+		want      bool
+	}{
+		{name: "ip", allowlist: []string{"192.0.2.1"}, subjects: subjects("192.0.2.1", ""), want: true},
+		{name: "cidr", allowlist: []string{"192.0.2.0/24"}, subjects: subjects("192.0.2.9", ""), want: true},
+		{name: "ip outside the cidr", allowlist: []string{"192.0.2.0/24"}, subjects: subjects("198.51.100.1", ""), want: false},
+		{name: "api key", allowlist: []string{"secret"}, subjects: subjects("198.51.100.1", "secret"), want: true},
+		{name: "wrong api key", allowlist: []string{"secret"}, subjects: subjects("198.51.100.1", "guess"), want: false},
+		{name: "addresses are not allowlisted", allowlist: []string{address.Hex()}, subjects: subjects("198.51.100.1", ""), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := rateLimitAllowlisted(tt.allowlist, tt.subjects); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	hub, _ := newTestHub(t)
+	hub.config.RateLimit.Routes = map[string][]config.RateLimitRule{
+		"POST /pray/knock": {
+			{Key: RateLimitKeyIP, Algorithm: RateLimitAlgorithmSlidingWindow, Limit: 2, Window: time.Minute},
+		},
+	}
+
+	e := echo.New()
+	e.POST("/pray/knock", func(c echo.Context) error { return c.NoContent(http.StatusOK) }, hub.RateLimit)
+	e.POST("/pray/reply", func(c echo.Context) error { return c.NoContent(http.StatusOK) }, hub.RateLimit)
+
+	tests := []struct {
+		path          string
+		want          int
+		wantRemaining string
+	}{
+		{path: "/pray/knock", want: http.StatusOK, wantRemaining: "1"},
+		{path: "/pray/knock", want: http.StatusOK, wantRemaining: "0"},
+		{path: "/pray/knock", want: http.StatusTooManyRequests, wantRemaining: "0"},
+		// routes without rules are not limited
+		{path: "/pray/reply", want: http.StatusOK},
+	}
+
+	for i, tt := range tests {
+		request := httptest.NewRequest(http.MethodPost, tt.path, nil)
+		request.RemoteAddr = "192.0.2.1:1234"
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+
+		if recorder.Code != tt.want {
+			t.Errorf("request %d: got status %d, want %d", i, recorder.Code, tt.want)
+		}
+
+		if got := recorder.Header().Get(HeaderRateLimitRemaining); got != tt.wantRemaining {
+			t.Errorf("request %d: got remaining %q, want %q", i, got, tt.wantRemaining)
+		}
+
+		if tt.want == http.StatusTooManyRequests && recorder.Header().Get(echo.HeaderRetryAfter) == "" {
+			t.Errorf("request %d: missing %s", i, echo.HeaderRetryAfter)
+		}
+	}
+}
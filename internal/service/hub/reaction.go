@@ -0,0 +1,162 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+
+	"github.com/brucexc/pray-to-earn/internal/database"
+	"github.com/brucexc/pray-to-earn/internal/service/hub/model/errorx"
+	"github.com/brucexc/pray-to-earn/schema"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type ReactRequest struct {
+	ID      string         `param:"id" validate:"required"`
+	Address common.Address `json:"address" validate:"required"`
+	Type    string         `json:"type" validate:"required"`
+	// TxHash optionally points to a PRAY transfer from the reactor to the note author.
+	TxHash *common.Hash `json:"tx_hash"`
+}
+
+type ReactResponse struct {
+	Counts map[string]int64 `json:"counts"`
+	Tip    *schema.Tip      `json:"tip,omitempty"`
+}
+
+func (h *Hub) React(c echo.Context) error {
+	var request ReactRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	if !slices.Contains(schema.ReactionTypes, request.Type) {
+		return errorx.ValidationFailedError(c, fmt.Errorf("type: must be one of %v", schema.ReactionTypes))
+	}
+
+	// reactions count towards the note and may mark it answered, so they must come from the reacting address
+	reactor, err := signer(c)
+	if err != nil {
+		return errorx.UnauthorizedError(c, err)
+	}
+
+	if reactor != request.Address {
+		return errorx.ForbiddenError(c, fmt.Errorf("reactions must be signed by the reacting address"))
+	}
+
+	ctx := c.Request().Context()
+
+	note, err := h.databaseClient.FindNote(ctx, request.ID, nil)
+	if err != nil && !errors.Is(err, database.ErrorRowNotFound) {
+		zap.L().Error("failed to find note", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if note == nil || note.Hidden {
+		return errorx.NotFoundError(c, fmt.Errorf("note %s not found", request.ID))
+	}
+
+	if err := h.checkCircleReader(c, note, request.Address); err != nil {
+		return circleReaderError(c, note.MessageID, err)
+	}
+
+	var tip *schema.Tip
+
+	if request.TxHash != nil {
+		// a tip is paid straight to the author, so accepting one would confirm who wrote an anonymous note
+		if note.Anonymous {
+			return errorx.BadParamsError(c, fmt.Errorf("anonymous notes cannot be tipped"))
+		}
+
+		if note.Address == request.Address {
+			return errorx.BadParamsError(c, fmt.Errorf("cannot tip your own note"))
+		}
+
+		if tip, err = h.verifyTip(ctx, request, note); err != nil {
+			if errors.Is(err, ErrBadPayment) {
+				return errorx.BadPaymentError(c, err)
+			}
+
+			zap.L().Error("failed to verify tip", zap.String("tx_hash", request.TxHash.Hex()), zap.Error(err))
+
+			return errorx.InternalError(c)
+		}
+	}
+
+	if _, err := h.databaseClient.SaveReaction(ctx, &schema.Reaction{
+		NoteID:  request.ID,
+		Address: request.Address,
+		Type:    request.Type,
+	}); err != nil {
+		zap.L().Error("failed to save reaction", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	counts, err := h.databaseClient.CountReactions(ctx, request.ID)
+	if err != nil {
+		zap.L().Error("failed to count reactions", zap.String("id", request.ID), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	zap.L().Info("reacted to note", zap.String("id", request.ID), zap.String("type", request.Type), zap.Any("tip", tip))
+
+	return c.JSON(http.StatusOK, Response{
+		Data: ReactResponse{
+			Counts: counts,
+			Tip:    tip,
+		},
+	})
+}
+
+// verifyTip checks the reaction transaction moved enough PRAY to the note author and records it in the tipping ledger.
+func (h *Hub) verifyTip(ctx context.Context, request ReactRequest, note *schema.Note) (*schema.Tip, error) {
+	if _, err := h.databaseClient.FindTip(ctx, *request.TxHash); err == nil {
+		return nil, fmt.Errorf("%w: transaction %s was already used", ErrBadPayment, request.TxHash.Hex())
+	} else if !errors.Is(err, database.ErrorRowNotFound) {
+		return nil, fmt.Errorf("find tip: %w", err)
+	}
+
+	transfers, err := h.findTransfers(ctx, *request.TxHash)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBadPayment, err)
+	}
+
+	amount := new(big.Int)
+	for _, transfer := range transfers {
+		if transfer.From == request.Address && transfer.To == note.Address {
+			amount.Add(amount, transfer.Amount)
+		}
+	}
+
+	minTip := new(big.Int).Mul(big.NewInt(1e18), big.NewInt(h.config.Reaction.MinTip))
+	if amount.Cmp(minTip) < 0 {
+		return nil, fmt.Errorf("%w: tip to the note author of at least %d PRAY not found", ErrBadPayment, h.config.Reaction.MinTip)
+	}
+
+	tip := &schema.Tip{
+		TransactionHash: *request.TxHash,
+		NoteID:          note.MessageID,
+		From:            request.Address,
+		To:              note.Address,
+		Amount:          amount,
+	}
+
+	if err := h.databaseClient.SaveTip(ctx, tip); err != nil {
+		return nil, fmt.Errorf("save tip: %w", err)
+	}
+
+	return tip, nil
+}
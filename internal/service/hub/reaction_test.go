@@ -0,0 +1,60 @@
+package hub
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+)
+
+func TestReactRequiresReactorSignature(t *testing.T) {
+	t.Parallel()
+
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	other := common.HexToAddress("0x0000000000000000000000000000000000000002")
+
+	tests := []struct {
+		name   string
+		signer *common.Address
+		want   int
+	}{
+		{name: "unsigned", want: http.StatusUnauthorized},
+		{name: "signed by another address", signer: &other, want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hub, _ := newTestHub(t)
+
+			e := echo.New()
+			e.Validator = defaultValidator
+
+			body := `{"address":"` + address.Hex() + `","type":"amen"}`
+			request := httptest.NewRequest(http.MethodPost, "/pray/notes/note/react", strings.NewReader(body))
+			request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			recorder := httptest.NewRecorder()
+
+			c := e.NewContext(request, recorder)
+			c.SetParamNames("id")
+			c.SetParamValues("note")
+
+			if tt.signer != nil {
+				c.Set(contextKeySigner, &verifiedSigner{address: *tt.signer})
+			}
+
+			if err := hub.React(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if recorder.Code != tt.want {
+				t.Errorf("got status %d, want %d: %s", recorder.Code, tt.want, recorder.Body.String())
+			}
+		})
+	}
+}
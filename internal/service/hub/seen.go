@@ -0,0 +1,98 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const seenTTL = 30 * 24 * time.Hour
+
+var ErrNoMessage = errors.New("no message found")
+
+func authoredKey(address common.Address) string {
+	return fmt.Sprintf("notes:authored:%s", address.Hex())
+}
+
+func seenKey(address common.Address) string {
+	return fmt.Sprintf("notes:seen:%s", address.Hex())
+}
+
+// pickUnseenMessageID returns a message ID from the pool the address neither wrote nor was served, or an empty string.
+func (h *Hub) pickUnseenMessageID(ctx context.Context, address common.Address, pool string) (string, error) {
+	candidates, err := h.redisClient.SRandMemberN(ctx, pool, h.config.Selection.SampleSize).Result()
+	if err != nil {
+		return "", fmt.Errorf("sample messages: %w", err)
+	}
+
+	if len(candidates) == 0 {
+		return "", nil
+	}
+
+	members := make([]interface{}, 0, len(candidates))
+	for _, candidate := range candidates {
+		members = append(members, candidate)
+	}
+
+	pipeline := h.redisClient.Pipeline()
+	seen := pipeline.SMIsMember(ctx, seenKey(address), members...)
+	authored := pipeline.SMIsMember(ctx, authoredKey(address), members...)
+
+	if _, err := pipeline.Exec(ctx); err != nil {
+		return "", fmt.Errorf("check seen messages: %w", err)
+	}
+
+	eligible := make([]string, 0, len(candidates))
+	for i, candidate := range candidates {
+		if !seen.Val()[i] && !authored.Val()[i] {
+			eligible = append(eligible, candidate)
+		}
+	}
+
+	// the sample was all stale, look at the whole pool instead
+	if len(eligible) == 0 {
+		return h.pickMessageIDExcluding(ctx, pool, seenKey(address), authoredKey(address))
+	}
+
+	return h.selector.Select(ctx, eligible)
+}
+
+// pickMessageIDExcluding returns a message ID from the pool that is not a member of any of the given sets, or an empty string.
+func (h *Hub) pickMessageIDExcluding(ctx context.Context, pool string, keys ...string) (string, error) {
+	remaining, err := h.redisClient.SDiff(ctx, append([]string{pool}, keys...)...).Result()
+	if err != nil {
+		return "", fmt.Errorf("diff messages: %w", err)
+	}
+
+	if len(remaining) == 0 {
+		return "", nil
+	}
+
+	// keep the selector input bounded however large the pool grows
+	if sampleSize := int(h.config.Selection.SampleSize); len(remaining) > sampleSize {
+		rand.Shuffle(len(remaining), func(i, j int) {
+			remaining[i], remaining[j] = remaining[j], remaining[i]
+		})
+
+		remaining = remaining[:sampleSize]
+	}
+
+	return h.selector.Select(ctx, remaining)
+}
+
+func (h *Hub) markMessageSeen(ctx context.Context, address common.Address, messageID string) error {
+	pipeline := h.redisClient.TxPipeline()
+	pipeline.SAdd(ctx, seenKey(address), messageID)
+	pipeline.Expire(ctx, seenKey(address), seenTTL)
+	pipeline.HIncrBy(ctx, messageStatsKey(messageID), statsFieldViews, 1)
+
+	if _, err := pipeline.Exec(ctx); err != nil {
+		return fmt.Errorf("mark message seen: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package hub
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	SelectionStrategyUniform  = "uniform"
+	SelectionStrategyWeighted = "weighted"
+
+	statsFieldViews     = "views"
+	statsFieldReplies   = "replies"
+	statsFieldCreatedAt = "created_at"
+)
+
+// Selector picks one message ID out of a non-empty list of eligible candidates.
+type Selector interface {
+	Select(ctx context.Context, candidates []string) (string, error)
+}
+
+func NewSelector(conf *config.Selection, redisClient *redis.Client) (Selector, error) {
+	switch conf.Strategy {
+	case SelectionStrategyUniform:
+		return &UniformSelector{}, nil
+	case SelectionStrategyWeighted:
+		return &WeightedSelector{
+			redisClient: redisClient,
+			halfLife:    conf.HalfLife,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported selection strategy %s", conf.Strategy)
+	}
+}
+
+var _ Selector = (*UniformSelector)(nil)
+
+type UniformSelector struct{}
+
+func (s *UniformSelector) Select(_ context.Context, candidates []string) (string, error) {
+	return candidates[rand.Intn(len(candidates))], nil
+}
+
+var _ Selector = (*WeightedSelector)(nil)
+
+// WeightedSelector favors notes that were rarely served or replied to, and decays older notes by a half-life.
+type WeightedSelector struct {
+	redisClient *redis.Client
+	halfLife    time.Duration
+}
+
+func (s *WeightedSelector) Select(ctx context.Context, candidates []string) (string, error) {
+	pipeline := s.redisClient.Pipeline()
+
+	commands := make([]*redis.SliceCmd, 0, len(candidates))
+	for _, candidate := range candidates {
+		commands = append(commands, pipeline.HMGet(ctx, messageStatsKey(candidate), statsFieldViews, statsFieldReplies, statsFieldCreatedAt))
+	}
+
+	if _, err := pipeline.Exec(ctx); err != nil {
+		return "", fmt.Errorf("load message stats: %w", err)
+	}
+
+	var (
+		now     = time.Now()
+		weights = make([]float64, len(candidates))
+		total   float64
+	)
+
+	for i, command := range commands {
+		values := command.Val()
+
+		views := parseStat(values[0])
+		replies := parseStat(values[1])
+		age := 0.0
+
+		// notes stored before stats existed count as brand new rather than never being picked
+		if createdAt := parseStat(values[2]); createdAt > 0 {
+			age = now.Sub(time.Unix(createdAt, 0)).Seconds()
+		}
+
+		weights[i] = math.Exp2(-age/s.halfLife.Seconds()) / float64((1+views)*(1+replies))
+		total += weights[i]
+	}
+
+	if total <= 0 {
+		return candidates[rand.Intn(len(candidates))], nil
+	}
+
+	target := rand.Float64() * total
+	for i, weight := range weights {
+		if target -= weight; target < 0 {
+			return candidates[i], nil
+		}
+	}
+
+	return candidates[len(candidates)-1], nil
+}
+
+func messageStatsKey(messageID string) string {
+	return fmt.Sprintf("message:stats:%s", messageID)
+}
+
+func parseStat(value interface{}) int64 {
+	text, ok := value.(string)
+	if !ok {
+		return 0
+	}
+
+	number, _ := strconv.ParseInt(text, 10, 64)
+
+	return number
+}
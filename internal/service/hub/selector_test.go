@@ -0,0 +1,151 @@
+package hub
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/internal/config"
+)
+
+func TestNewSelector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		strategy string
+		want     Selector
+		wantErr  bool
+	}{
+		{strategy: SelectionStrategyUniform, want: &UniformSelector{}},
+		{strategy: SelectionStrategyWeighted, want: &WeightedSelector{}},
+		{strategy: "newest", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			t.Parallel()
+
+			selector, err := NewSelector(&config.Selection{Strategy: tt.strategy, HalfLife: time.Hour}, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error: got %v, want error %t", err, tt.wantErr)
+			}
+
+			switch tt.want.(type) {
+			case *UniformSelector:
+				if _, ok := selector.(*UniformSelector); !ok {
+					t.Errorf("got %T, want *UniformSelector", selector)
+				}
+			case *WeightedSelector:
+				if _, ok := selector.(*WeightedSelector); !ok {
+					t.Errorf("got %T, want *WeightedSelector", selector)
+				}
+			}
+		})
+	}
+}
+
+func TestWeightedSelector(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	type stats struct {
+		views, replies int64
+		age            time.Duration
+	}
+
+	tests := []struct {
+		name  string
+		stats map[string]stats
+		// want is the candidate expected to win by a wide margin
+		want string
+	}{
+		{
+			name: "fewer views",
+			stats: map[string]stats{
+				"fresh": {views: 0},
+				"worn":  {views: 200},
+			},
+			want: "fresh",
+		},
+		{
+			name: "fewer replies",
+			stats: map[string]stats{
+				"quiet": {replies: 0},
+				"busy":  {replies: 200},
+			},
+			want: "quiet",
+		},
+		{
+			name: "newer",
+			stats: map[string]stats{
+				"new": {age: time.Hour},
+				"old": {age: 20 * 72 * time.Hour},
+			},
+			want: "new",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hub, _ := newTestHub(t)
+			ctx := context.Background()
+
+			candidates := make([]string, 0, len(tt.stats))
+
+			for id, stat := range tt.stats {
+				candidates = append(candidates, id)
+
+				if err := hub.redisClient.HSet(ctx, messageStatsKey(id),
+					statsFieldViews, stat.views,
+					statsFieldReplies, stat.replies,
+					statsFieldCreatedAt, strconv.FormatInt(now.Add(-stat.age).Unix(), 10),
+				).Err(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			selector := &WeightedSelector{redisClient: hub.redisClient, halfLife: 72 * time.Hour}
+
+			picks := make(map[string]int)
+
+			for range 200 {
+				id, err := selector.Select(ctx, candidates)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				picks[id]++
+			}
+
+			if picks[tt.want] < 190 {
+				t.Errorf("picks: got %v, want %s nearly every time", picks, tt.want)
+			}
+		})
+	}
+}
+
+func TestUniformSelector(t *testing.T) {
+	t.Parallel()
+
+	candidates := []string{"a", "b", "c"}
+	picks := make(map[string]int)
+
+	for range 300 {
+		id, err := (&UniformSelector{}).Select(context.Background(), candidates)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		picks[id]++
+	}
+
+	for _, id := range candidates {
+		if picks[id] == 0 {
+			t.Errorf("picks: got %v, want every candidate picked", picks)
+		}
+	}
+}
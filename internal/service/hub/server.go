@@ -8,6 +8,7 @@ import (
 	"github.com/redis/go-redis/v9"
 
 	"github.com/brucexc/pray-to-earn/internal/config"
+	"github.com/brucexc/pray-to-earn/internal/database"
 	"github.com/brucexc/pray-to-earn/internal/service"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/labstack/echo/v4"
@@ -30,14 +31,40 @@ func (s *Server) Name() string {
 	return Name
 }
 
-func (s *Server) Run(_ context.Context) error {
+func (s *Server) Run(ctx context.Context) error {
+	go s.hub.RunArchiver(ctx)
+	go s.hub.RunUnsealer(ctx)
+	go s.hub.RunMintRetrier(ctx)
+
 	address := net.JoinHostPort(DefaultHost, DefaultPort)
 
 	return s.httpServer.Start(address)
 }
 
-func NewServer(conf *config.File, ethereumClient *ethclient.Client, redisClient *redis.Client) (service.Server, error) {
-	hub, err := NewHub(context.Background(), *conf, ethereumClient, redisClient)
+// ipExtractor reads the client IP from X-Forwarded-For only behind the configured proxies,
+// otherwise a client could pick any IP to dodge the limits keyed on it.
+func ipExtractor(conf *config.Proxy) echo.IPExtractor {
+	if len(conf.TrustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+
+	for _, proxy := range conf.TrustedProxies {
+		// the config is validated to hold CIDR ranges only
+		_, network, _ := net.ParseCIDR(proxy)
+		options = append(options, echo.TrustIPRange(network))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+func NewServer(conf *config.File, databaseClient *database.Client, ethereumClient *ethclient.Client, redisClient *redis.Client) (service.Server, error) {
+	hub, err := NewHub(context.Background(), *conf, databaseClient, ethereumClient, redisClient)
 	if err != nil {
 		return nil, fmt.Errorf("new hub: %w", err)
 	}
@@ -50,14 +77,47 @@ func NewServer(conf *config.File, ethereumClient *ethclient.Client, redisClient
 	instance.httpServer.HideBanner = true
 	instance.httpServer.HidePort = true
 	instance.httpServer.Validator = defaultValidator
+	instance.httpServer.IPExtractor = ipExtractor(conf.Proxy)
 	instance.httpServer.Use(middleware.CORSWithConfig(middleware.DefaultCORSConfig))
 
-	nodes := instance.httpServer.Group("/pray")
+	nodes := instance.httpServer.Group("/pray", instance.hub.Authenticate, instance.hub.RateLimit)
 	{
+		nodes.GET("/challenge", instance.hub.GetChallenge)
 		nodes.POST("/knock", instance.hub.Knock)
 		nodes.POST("/reply", instance.hub.Reply)
 		nodes.POST("/peekNote", instance.hub.PeekNote)
 		nodes.POST("/faucet", instance.hub.Faucet)
+		nodes.GET("/notes", instance.hub.GetNotes)
+		nodes.GET("/notes/:id", instance.hub.GetNote)
+		nodes.PATCH("/notes/:id", instance.hub.EditNote)
+		nodes.DELETE("/notes/:id", instance.hub.DeleteNote)
+		nodes.GET("/notes/:id/replies", instance.hub.GetNoteReplies)
+		nodes.POST("/notes/:id/react", instance.hub.React)
+		nodes.POST("/notes/:id/answer", instance.hub.AnswerNote)
+		nodes.POST("/notes/:id/intercede", instance.hub.Intercede)
+		nodes.GET("/answered", instance.hub.GetAnsweredNotes)
+		nodes.GET("/search", instance.hub.SearchNotes)
+		nodes.GET("/archive", instance.hub.GetArchivedNotes)
+		nodes.GET("/capsules", instance.hub.GetCapsules)
+		nodes.GET("/inbox", instance.hub.GetInbox)
+		nodes.POST("/inbox/read", instance.hub.ReadInbox)
+		nodes.GET("/profile/:address", instance.hub.GetProfile)
+		nodes.PUT("/profile/:address", instance.hub.UpdateProfile)
+		nodes.Static("/avatars", conf.Profile.AvatarDir)
+		nodes.GET("/circles", instance.hub.GetCircles)
+		nodes.POST("/circles", instance.hub.CreateCircle)
+		nodes.POST("/circles/:id/invite", instance.hub.InviteCircle)
+		nodes.POST("/circles/:id/join", instance.hub.JoinCircle)
+		nodes.POST("/chains", instance.hub.StartChain)
+		nodes.GET("/chains/:id", instance.hub.GetChain)
+		nodes.POST("/chains/:id/append", instance.hub.AppendChain)
+		nodes.PUT("/keys", instance.hub.RegisterEncryptionKey)
+		nodes.GET("/keys/:address", instance.hub.GetEncryptionKey)
+		nodes.GET("/encrypted", instance.hub.GetEncryptedNotes)
+		nodes.POST("/encrypted", instance.hub.SendEncryptedNote)
+		nodes.GET("/encrypted/:id", instance.hub.GetEncryptedNote)
+		nodes.GET("/admin/notes/:id/revisions", instance.hub.GetNoteRevisions)
+		nodes.GET("/admin/notes/:id/author", instance.hub.GetNoteAuthor)
 	}
 
 	return &instance, nil
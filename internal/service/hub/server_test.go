@@ -0,0 +1,64 @@
+package hub
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/brucexc/pray-to-earn/internal/config"
+	"github.com/labstack/echo/v4"
+)
+
+func TestIPExtractor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		proxies    []string
+		remoteAddr string
+		forwarded  string
+		want       string
+	}{
+		{
+			name:       "no proxies ignores the header",
+			remoteAddr: "203.0.113.7:1234",
+			forwarded:  "198.51.100.1",
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "trusted proxy",
+			proxies:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.1.2.3:1234",
+			forwarded:  "198.51.100.1",
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "untrusted peer",
+			proxies:    []string{"10.0.0.0/8"},
+			remoteAddr: "203.0.113.7:1234",
+			forwarded:  "198.51.100.1",
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "spoofed hops before the proxy",
+			proxies:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.1.2.3:1234",
+			forwarded:  "192.0.2.99, 198.51.100.1",
+			want:       "198.51.100.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			request := httptest.NewRequest(http.MethodGet, "/pray/challenge", nil)
+			request.RemoteAddr = tt.remoteAddr
+			request.Header.Set(echo.HeaderXForwardedFor, tt.forwarded)
+
+			if got := ipExtractor(&config.Proxy{TrustedProxies: tt.proxies})(request); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
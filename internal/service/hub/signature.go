@@ -0,0 +1,187 @@
+package hub
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HeaderAddress   = "X-Pray-Address"
+	HeaderTimestamp = "X-Pray-Timestamp"
+	HeaderNonce     = "X-Pray-Nonce"
+	HeaderSignature = "X-Pray-Signature"
+
+	signatureWindow = 5 * time.Minute
+
+	minNonceLength = 16
+	maxNonceLength = 64
+	// maxSignedBody bounds the body the hub buffers to check its hash, an avatar upload is the largest signed body.
+	maxSignedBody = 4 << 20
+
+	contextKeySigner = "signer"
+)
+
+var ErrSignatureRequired = errors.New("signature required")
+
+// SignedMessage is the text a wallet signs with personal_sign to authenticate a request.
+// It binds the signature to one request: the method, the request URI with its query string and the sha256 of the body,
+// the path carries the note or circle ID the request acts on. The nonce may be used once, so a captured request cannot be replayed.
+func SignedMessage(method, uri string, body []byte, address common.Address, timestamp int64, nonce string) string {
+	digest := sha256.Sum256(body)
+
+	return fmt.Sprintf("pray-to-earn:%s:%d:%s\n%s %s\n%s", address.Hex(), timestamp, nonce, method, uri, hex.EncodeToString(digest[:]))
+}
+
+type verifiedSigner struct {
+	address   common.Address
+	publicKey *ecdsa.PublicKey
+	err       error
+}
+
+// Authenticate verifies the signature headers once per request and spends the nonce,
+// handlers and later middlewares read the outcome through signer.
+func (h *Hub) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var result verifiedSigner
+
+		result.address, result.publicKey, result.err = h.verifySignature(c)
+
+		c.Set(contextKeySigner, &result)
+
+		return next(c)
+	}
+}
+
+func (h *Hub) verifySignature(c echo.Context) (common.Address, *ecdsa.PublicKey, error) {
+	request := c.Request()
+	headers := request.Header
+
+	if headers.Get(HeaderAddress) == "" && headers.Get(HeaderSignature) == "" {
+		return common.Address{}, nil, ErrSignatureRequired
+	}
+
+	if !common.IsHexAddress(headers.Get(HeaderAddress)) {
+		return common.Address{}, nil, fmt.Errorf("invalid %s header", HeaderAddress)
+	}
+
+	address := common.HexToAddress(headers.Get(HeaderAddress))
+
+	timestamp, err := strconv.ParseInt(headers.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("invalid %s header: %w", HeaderTimestamp, err)
+	}
+
+	if age := time.Since(time.Unix(timestamp, 0)); age > signatureWindow || age < -signatureWindow {
+		return common.Address{}, nil, fmt.Errorf("signature expired")
+	}
+
+	nonce := headers.Get(HeaderNonce)
+	if len(nonce) < minNonceLength || len(nonce) > maxNonceLength {
+		return common.Address{}, nil, fmt.Errorf("invalid %s header: must be %d to %d characters", HeaderNonce, minNonceLength, maxNonceLength)
+	}
+
+	signature, err := hexutil.Decode(headers.Get(HeaderSignature))
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("invalid %s header: %w", HeaderSignature, err)
+	}
+
+	body, err := readSignedBody(request)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	publicKey, err := recoverPublicKey(SignedMessage(request.Method, request.URL.RequestURI(), body, address, timestamp, nonce), signature)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	if crypto.PubkeyToAddress(*publicKey) != address {
+		return common.Address{}, nil, fmt.Errorf("signature does not match address")
+	}
+
+	// the nonce outlives the window on both sides of the timestamp, so a replay is either expired or already spent
+	spent, err := h.redisClient.SetNX(request.Context(), signatureNonceKey(address, nonce), timestamp, 2*signatureWindow).Result()
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("spend nonce: %w", err)
+	}
+
+	if !spent {
+		return common.Address{}, nil, fmt.Errorf("nonce already used")
+	}
+
+	return address, publicKey, nil
+}
+
+// readSignedBody reads the body for its hash and leaves it readable for the handler.
+func readSignedBody(request *http.Request) ([]byte, error) {
+	if request.Body == nil {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(request.Body, maxSignedBody+1))
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+
+	if len(body) > maxSignedBody {
+		return nil, fmt.Errorf("signed body must not exceed %d bytes", maxSignedBody)
+	}
+
+	request.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+func signatureNonceKey(address common.Address, nonce string) string {
+	return fmt.Sprintf("signature:nonce:%s:%s", address.Hex(), nonce)
+}
+
+// signer returns the wallet that signed the request, or ErrSignatureRequired if the request is unsigned.
+func signer(c echo.Context) (common.Address, error) {
+	address, _, err := signerKey(c)
+
+	return address, err
+}
+
+// signerKey is signer that also returns the public key recovered from the signature.
+func signerKey(c echo.Context) (common.Address, *ecdsa.PublicKey, error) {
+	result, ok := c.Get(contextKeySigner).(*verifiedSigner)
+	if !ok {
+		return common.Address{}, nil, ErrSignatureRequired
+	}
+
+	return result.address, result.publicKey, result.err
+}
+
+func recoverPublicKey(message string, signature []byte) (*ecdsa.PublicKey, error) {
+	if len(signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("invalid signature length %d", len(signature))
+	}
+
+	// wallets return the recovery id as 27 or 28
+	signature = common.CopyBytes(signature)
+	if signature[crypto.RecoveryIDOffset] >= 27 {
+		signature[crypto.RecoveryIDOffset] -= 27
+	}
+
+	publicKey, err := crypto.SigToPub(accounts.TextHash([]byte(message)), signature)
+	if err != nil {
+		return nil, fmt.Errorf("recover public key: %w", err)
+	}
+
+	return publicKey, nil
+}
@@ -0,0 +1,223 @@
+package hub
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/labstack/echo/v4"
+)
+
+type signedTestRequest struct {
+	method, uri, body string
+	timestamp         time.Time
+	nonce             string
+}
+
+// signTestRequest builds a request signed by key the way a wallet client does.
+func signTestRequest(t *testing.T, key *ecdsa.PrivateKey, signed, sent signedTestRequest) *http.Request {
+	t.Helper()
+
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	message := SignedMessage(signed.method, signed.uri, []byte(signed.body), address, signed.timestamp.Unix(), signed.nonce)
+
+	signature, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signature[crypto.RecoveryIDOffset] += 27
+
+	request := httptest.NewRequest(sent.method, sent.uri, strings.NewReader(sent.body))
+	request.Header.Set(HeaderAddress, address.Hex())
+	request.Header.Set(HeaderTimestamp, strconv.FormatInt(sent.timestamp.Unix(), 10))
+	request.Header.Set(HeaderNonce, sent.nonce)
+	request.Header.Set(HeaderSignature, hexutil.Encode(signature))
+
+	return request
+}
+
+func TestAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	now := time.Now()
+
+	base := signedTestRequest{
+		method:    http.MethodPatch,
+		uri:       "/pray/notes/a?x=1",
+		body:      `{"note":"amen"}`,
+		timestamp: now,
+		nonce:     "0123456789abcdef",
+	}
+
+	tests := []struct {
+		name    string
+		signed  func(r *signedTestRequest)
+		sent    func(r *signedTestRequest)
+		unsign  bool
+		want    common.Address
+		wantErr string
+	}{
+		{
+			name: "valid",
+			want: address,
+		},
+		{
+			name:    "unsigned",
+			unsign:  true,
+			wantErr: ErrSignatureRequired.Error(),
+		},
+		{
+			name:    "other method",
+			sent:    func(r *signedTestRequest) { r.method = http.MethodDelete },
+			wantErr: "signature does not match address",
+		},
+		{
+			name:    "other path",
+			sent:    func(r *signedTestRequest) { r.uri = "/pray/notes/b?x=1" },
+			wantErr: "signature does not match address",
+		},
+		{
+			name:    "other query",
+			sent:    func(r *signedTestRequest) { r.uri = "/pray/notes/a?x=2" },
+			wantErr: "signature does not match address",
+		},
+		{
+			name:    "other body",
+			sent:    func(r *signedTestRequest) { r.body = `{"note":"amen!"}` },
+			wantErr: "signature does not match address",
+		},
+		{
+			name: "expired",
+			signed: func(r *signedTestRequest) {
+				r.timestamp = now.Add(-2 * signatureWindow)
+			},
+			sent: func(r *signedTestRequest) {
+				r.timestamp = now.Add(-2 * signatureWindow)
+			},
+			wantErr: "signature expired",
+		},
+		{
+			name:    "short nonce",
+			signed:  func(r *signedTestRequest) { r.nonce = "1" },
+			sent:    func(r *signedTestRequest) { r.nonce = "1" },
+			wantErr: "invalid X-Pray-Nonce header",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hub, _ := newTestHub(t)
+
+			signed, sent := base, base
+			if tt.signed != nil {
+				tt.signed(&signed)
+			}
+
+			if tt.sent != nil {
+				tt.sent(&sent)
+			}
+
+			request := signTestRequest(t, key, signed, sent)
+			if tt.unsign {
+				request = httptest.NewRequest(sent.method, sent.uri, strings.NewReader(sent.body))
+			}
+
+			got, body, err := authenticate(hub, request)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error: got %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("error: got %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("signer: got %s, want %s", got.Hex(), tt.want.Hex())
+			}
+
+			if body != sent.body {
+				t.Errorf("body: got %q, want it left readable as %q", body, sent.body)
+			}
+		})
+	}
+}
+
+func TestAuthenticateReplay(t *testing.T) {
+	t.Parallel()
+
+	hub, _ := newTestHub(t)
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := signedTestRequest{
+		method:    http.MethodPost,
+		uri:       "/pray/circles/c/join",
+		timestamp: time.Now(),
+		nonce:     "fedcba9876543210",
+	}
+
+	if _, _, err := authenticate(hub, signTestRequest(t, key, request, request)); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+
+	if _, _, err := authenticate(hub, signTestRequest(t, key, request, request)); err == nil || !strings.Contains(err.Error(), "nonce already used") {
+		t.Fatalf("replayed request: got %v, want the nonce rejected", err)
+	}
+}
+
+// authenticate runs a request through the middleware and returns what the handler sees.
+func authenticate(hub *Hub, request *http.Request) (common.Address, string, error) {
+	var (
+		address common.Address
+		body    []byte
+		err     error
+	)
+
+	c := echo.New().NewContext(request, httptest.NewRecorder())
+
+	_ = hub.Authenticate(func(c echo.Context) error {
+		if address, err = signer(c); err != nil {
+			return nil
+		}
+
+		if body, err = io.ReadAll(c.Request().Body); err != nil {
+			return nil
+		}
+
+		// a second lookup in the same request reuses the verification instead of spending the nonce again
+		if again, againErr := signer(c); again != address || againErr != nil {
+			err = errors.Join(errors.New("second lookup disagrees"), againErr)
+		}
+
+		return nil
+	})(c)
+
+	return address, string(body), err
+}
@@ -0,0 +1,156 @@
+package hub
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	spamScopeAddress = "address"
+	spamScopeGlobal  = "global"
+
+	simhashGlobalKey = "simhash:global"
+	simhashShingle   = 3
+)
+
+type SpamDecision struct {
+	Duplicate  bool    `json:"duplicate"`
+	Similarity float64 `json:"similarity"`
+	Scope      string  `json:"scope,omitempty"`
+}
+
+// simhash returns a 64-bit fingerprint of the note built from character shingles,
+// so it works for languages without word boundaries as well.
+// Notes with too few letters and digits, such as emoji or punctuation only, are shingled on every visible rune instead.
+func simhash(note string) uint64 {
+	note = strings.ToLower(note)
+	runes := make([]rune, 0, len(note))
+
+	for _, r := range note {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			runes = append(runes, r)
+		}
+	}
+
+	if len(runes) < simhashShingle {
+		runes = runes[:0]
+
+		for _, r := range note {
+			if !unicode.IsSpace(r) {
+				runes = append(runes, r)
+			}
+		}
+	}
+
+	if len(runes) < simhashShingle {
+		runes = append(runes, make([]rune, simhashShingle-len(runes))...)
+	}
+
+	var weights [64]int
+
+	for i := 0; i+simhashShingle <= len(runes); i++ {
+		hash := fnv.New64a()
+		_, _ = hash.Write([]byte(string(runes[i : i+simhashShingle])))
+		sum := hash.Sum64()
+
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var fingerprint uint64
+
+	for bit, weight := range weights {
+		if weight > 0 {
+			fingerprint |= 1 << bit
+		}
+	}
+
+	return fingerprint
+}
+
+func simhashAddressKey(address common.Address) string {
+	return fmt.Sprintf("simhash:address:%s", address.Hex())
+}
+
+// checkDuplicate compares the note against recent notes of the address and of everyone.
+func (h *Hub) checkDuplicate(ctx context.Context, address common.Address, note string) (*SpamDecision, error) {
+	fingerprint := simhash(note)
+	decision := &SpamDecision{}
+
+	for _, scope := range h.spamScopes(address) {
+		values, err := h.redisClient.LRange(ctx, scope.key, 0, scope.limit-1).Result()
+		if err != nil {
+			return nil, fmt.Errorf("load %s fingerprints: %w", scope.name, err)
+		}
+
+		for _, value := range values {
+			other, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				continue
+			}
+
+			distance := bits.OnesCount64(fingerprint ^ other)
+			similarity := 1 - float64(distance)/64
+
+			if similarity > decision.Similarity {
+				decision.Similarity = similarity
+				decision.Scope = scope.name
+			}
+
+			if distance <= h.config.Spam.Threshold {
+				decision.Duplicate = true
+			}
+		}
+
+		if decision.Duplicate {
+			break
+		}
+	}
+
+	return decision, nil
+}
+
+// recordFingerprint keeps the fingerprint of an accepted note for future checks.
+func (h *Hub) recordFingerprint(ctx context.Context, address common.Address, note string) error {
+	fingerprint := strconv.FormatUint(simhash(note), 10)
+	pipeline := h.redisClient.TxPipeline()
+
+	for _, scope := range h.spamScopes(address) {
+		pipeline.LPush(ctx, scope.key, fingerprint)
+		pipeline.LTrim(ctx, scope.key, 0, scope.limit-1)
+	}
+
+	// addresses that stop knocking should not keep their fingerprints forever
+	pipeline.Expire(ctx, simhashAddressKey(address), h.config.Spam.AddressTTL)
+
+	if _, err := pipeline.Exec(ctx); err != nil {
+		return fmt.Errorf("record fingerprint: %w", err)
+	}
+
+	return nil
+}
+
+type spamScope struct {
+	name  string
+	key   string
+	limit int64
+}
+
+func (h *Hub) spamScopes(address common.Address) []spamScope {
+	return []spamScope{
+		{spamScopeAddress, simhashAddressKey(address), h.config.Spam.RecentPerAddress},
+		{spamScopeGlobal, simhashGlobalKey, h.config.Spam.RecentGlobal},
+	}
+}
@@ -0,0 +1,167 @@
+package hub
+
+import (
+	"context"
+	"math/bits"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestSimhash(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a, b string
+		// same asks for identical fingerprints, otherwise they must differ by more than the default threshold
+		same bool
+	}{
+		{
+			name: "identical",
+			a:    "please pray for my mother",
+			b:    "please pray for my mother",
+			same: true,
+		},
+		{
+			name: "case and punctuation",
+			a:    "Please pray for my mother!",
+			b:    "please, pray for my mother",
+			same: true,
+		},
+		{
+			name: "different notes",
+			a:    "please pray for my mother",
+			b:    "grateful for a new job today",
+		},
+		{
+			name: "different emoji",
+			a:    "🙏🙏🙏",
+			b:    "🕯️🕊️🌅",
+		},
+		{
+			name: "emoji and punctuation",
+			a:    "🙏🙏🙏",
+			b:    "!!!???",
+		},
+		{
+			name: "different punctuation",
+			a:    "...",
+			b:    "?!?!",
+		},
+		{
+			name: "repeated emoji",
+			a:    "🙏 🙏 🙏",
+			b:    "🙏🙏🙏",
+			same: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			distance := bits.OnesCount64(simhash(tt.a) ^ simhash(tt.b))
+
+			if tt.same && distance != 0 {
+				t.Errorf("distance: got %d, want 0", distance)
+			}
+
+			if !tt.same && distance <= 3 {
+				t.Errorf("distance: got %d, want more than 3", distance)
+			}
+		})
+	}
+}
+
+func TestCheckDuplicate(t *testing.T) {
+	t.Parallel()
+
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	other := common.HexToAddress("0x0000000000000000000000000000000000000002")
+
+	tests := []struct {
+		name      string
+		recorded  []string
+		recorder  common.Address
+		note      string
+		want      bool
+		wantScope string
+	}{
+		{
+			name: "nothing recorded",
+			note: "please pray for my mother",
+		},
+		{
+			name:      "recorded by the same address",
+			recorded:  []string{"please pray for my mother"},
+			recorder:  address,
+			note:      "please pray for my mother",
+			want:      true,
+			wantScope: spamScopeAddress,
+		},
+		{
+			name:      "recorded by someone else",
+			recorded:  []string{"please pray for my mother"},
+			recorder:  other,
+			note:      "please pray for my mother",
+			want:      true,
+			wantScope: spamScopeGlobal,
+		},
+		{
+			name:     "different note",
+			recorded: []string{"please pray for my mother"},
+			recorder: address,
+			note:     "thank you for the rain on our farm this week",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hub, _ := newTestHub(t)
+			ctx := context.Background()
+
+			for _, note := range tt.recorded {
+				if err := hub.recordFingerprint(ctx, tt.recorder, note); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			decision, err := hub.checkDuplicate(ctx, address, tt.note)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if decision.Duplicate != tt.want || (tt.want && decision.Scope != tt.wantScope) {
+				t.Errorf("got duplicate %t in %q, want %t in %q", decision.Duplicate, decision.Scope, tt.want, tt.wantScope)
+			}
+		})
+	}
+}
+
+func TestRecordFingerprint(t *testing.T) {
+	t.Parallel()
+
+	hub, server := newTestHub(t)
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	ctx := context.Background()
+
+	// checking alone must not mark the note as seen
+	if _, err := hub.checkDuplicate(ctx, address, "please pray for my mother"); err != nil {
+		t.Fatal(err)
+	}
+
+	if server.Exists(simhashAddressKey(address)) || server.Exists(simhashGlobalKey) {
+		t.Fatal("check recorded a fingerprint")
+	}
+
+	if err := hub.recordFingerprint(ctx, address, "please pray for my mother"); err != nil {
+		t.Fatal(err)
+	}
+
+	if ttl := server.TTL(simhashAddressKey(address)); ttl != hub.config.Spam.AddressTTL {
+		t.Errorf("address fingerprints: got ttl %s, want %s", ttl, hub.config.Spam.AddressTTL)
+	}
+}
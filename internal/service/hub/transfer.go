@@ -0,0 +1,59 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/brucexc/pray-to-earn/contract"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrBadPayment = errors.New("bad payment")
+
+type Transfer struct {
+	From   common.Address
+	To     common.Address
+	Amount *big.Int
+}
+
+// findTransfers returns the PRAY Transfer events emitted by a transaction.
+func (h *Hub) findTransfers(ctx context.Context, txHash common.Hash) ([]Transfer, error) {
+	receipt, err := h.ethereumClient.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("get transaction receipt: %w", err)
+	}
+
+	var transfers []Transfer
+
+	for _, log := range receipt.Logs {
+		if log.Address != contract.AddressPray || len(log.Topics) != 3 || log.Topics[0] != contract.TransferEventSig {
+			continue
+		}
+
+		transfers = append(transfers, Transfer{
+			From:   common.HexToAddress(log.Topics[1].Hex()),
+			To:     common.HexToAddress(log.Topics[2].Hex()),
+			Amount: new(big.Int).SetBytes(log.Data),
+		})
+	}
+
+	return transfers, nil
+}
+
+// findBurn makes sure a transaction burns exactly amount of PRAY from the address.
+func (h *Hub) findBurn(ctx context.Context, txHash common.Hash, from common.Address, amount *big.Int) error {
+	transfers, err := h.findTransfers(ctx, txHash)
+	if err != nil {
+		return err
+	}
+
+	for _, transfer := range transfers {
+		if transfer.From == from && transfer.To == zeroAddress && transfer.Amount.Cmp(amount) == 0 {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: burn of %s from %s not found", ErrBadPayment, amount, from.Hex())
+}
@@ -0,0 +1,202 @@
+package hub
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/brucexc/pray-to-earn/internal/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+)
+
+func TestCheckHuman(t *testing.T) {
+	t.Parallel()
+
+	stub := httptest.NewServer(&StubVerifyServer{Token: "human"})
+	t.Cleanup(stub.Close)
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	tests := []struct {
+		name     string
+		provider string
+		endpoint string
+		required bool
+		signed   bool
+		trusted  bool
+		token    string
+		wantErr  error
+		// wantAnyErr is set for failures that carry no sentinel
+		wantAnyErr  bool
+		wantTrusted bool
+	}{
+		{
+			name:     "not required",
+			provider: HumanProviderStub,
+			endpoint: stub.URL,
+		},
+		{
+			name:     "verification turned off",
+			provider: HumanProviderNone,
+			required: true,
+		},
+		{
+			name:     "missing token",
+			provider: HumanProviderStub,
+			endpoint: stub.URL,
+			required: true,
+			wantErr:  ErrHumanRequired,
+		},
+		{
+			name:     "rejected token",
+			provider: HumanProviderStub,
+			endpoint: stub.URL,
+			required: true,
+			token:    "robot",
+			wantErr:  ErrHumanFailed,
+		},
+		{
+			name:     "accepted token",
+			provider: HumanProviderStub,
+			endpoint: stub.URL,
+			required: true,
+			token:    "human",
+		},
+		{
+			name:        "accepted token on a signed request starts a trusted session",
+			provider:    HumanProviderStub,
+			endpoint:    stub.URL,
+			required:    true,
+			signed:      true,
+			token:       "human",
+			wantTrusted: true,
+		},
+		{
+			name:        "trusted session skips verification",
+			provider:    HumanProviderStub,
+			endpoint:    stub.URL,
+			required:    true,
+			signed:      true,
+			trusted:     true,
+			wantTrusted: true,
+		},
+		{
+			name:        "trusted session needs a signed request",
+			provider:    HumanProviderStub,
+			endpoint:    stub.URL,
+			required:    true,
+			trusted:     true,
+			wantErr:     ErrHumanRequired,
+			wantTrusted: true,
+		},
+		{
+			name:       "verifier unavailable",
+			provider:   HumanProviderStub,
+			endpoint:   down.URL,
+			required:   true,
+			token:      "human",
+			wantAnyErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hub, server := newTestHub(t)
+			hub.config.Human.Provider = tt.provider
+			hub.config.Human.Endpoint = tt.endpoint
+
+			verifier, err := NewHumanVerifier(hub.config.Human)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			hub.humanVerifier = verifier
+
+			if tt.trusted {
+				if err := server.Set(humanTrustKey(address), "1"); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			request := httptest.NewRequest(http.MethodPost, "/pray/knock", nil)
+			if tt.token != "" {
+				request.Header.Set(HeaderHumanToken, tt.token)
+			}
+
+			c := echo.New().NewContext(request, httptest.NewRecorder())
+			if tt.signed {
+				c.Set(contextKeySigner, &verifiedSigner{address: address})
+			}
+
+			err = hub.checkHuman(c, address, tt.required)
+
+			switch {
+			case tt.wantAnyErr:
+				if err == nil || errors.Is(err, ErrHumanRequired) || errors.Is(err, ErrHumanFailed) {
+					t.Fatalf("error: got %v, want a verifier failure", err)
+				}
+			case !errors.Is(err, tt.wantErr):
+				t.Fatalf("error: got %v, want %v", err, tt.wantErr)
+			}
+
+			if trusted := server.Exists(humanTrustKey(address)); trusted != tt.wantTrusted {
+				t.Errorf("trusted session: got %t, want %t", trusted, tt.wantTrusted)
+			}
+		})
+	}
+}
+
+func TestNewHumanVerifier(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		provider string
+		want     HumanVerifier
+		wantErr  bool
+	}{
+		{name: "none", provider: HumanProviderNone, want: &NoopVerifier{}},
+		{name: "hcaptcha", provider: HumanProviderHCaptcha, want: &SiteVerifier{}},
+		{name: "turnstile", provider: HumanProviderTurnstile, want: &SiteVerifier{}},
+		{name: "unknown", provider: "recaptcha", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			verifier, err := NewHumanVerifier(&config.Human{Provider: tt.provider, Timeout: time.Second})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error: got %v, want error %t", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			switch tt.want.(type) {
+			case *NoopVerifier:
+				if _, ok := verifier.(*NoopVerifier); !ok {
+					t.Errorf("got %T, want %T", verifier, tt.want)
+				}
+			case *SiteVerifier:
+				site, ok := verifier.(*SiteVerifier)
+				if !ok {
+					t.Fatalf("got %T, want %T", verifier, tt.want)
+				}
+
+				if site.endpoint != humanVerifyEndpoints[tt.provider] {
+					t.Errorf("endpoint: got %s, want %s", site.endpoint, humanVerifyEndpoints[tt.provider])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package schema
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	BurnPurposePeek = "peek"
+	BurnPurposeGift = "gift"
+)
+
+// Burn is a PRAY burn transaction spent on a paid request, each transaction pays for one request only.
+type Burn struct {
+	ID              uint64         `json:"id"`
+	TransactionHash common.Hash    `json:"transaction_hash"`
+	Purpose         string         `json:"purpose"`
+	Address         common.Address `json:"address"`
+	Amount          *big.Int       `json:"amount"`
+	CreatedAt       int64          `json:"created_at"`
+}
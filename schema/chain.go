@@ -0,0 +1,30 @@
+package schema
+
+import "github.com/ethereum/go-ethereum/common"
+
+// A chain is open while it waits for its next contributor, assigned while one holds it and completed once it is full.
+const (
+	ChainStatusOpen      = "open"
+	ChainStatusAssigned  = "assigned"
+	ChainStatusCompleted = "completed"
+)
+
+// Chain is the state of a chain prayer note passed from knock to knock, every contributor appends one line.
+type Chain struct {
+	ID          string          `json:"id"`
+	Starter     common.Address  `json:"starter"`
+	Length      int             `json:"length"`
+	Status      string          `json:"status"`
+	Holder      *common.Address `json:"holder,omitempty"`
+	AssignedAt  int64           `json:"assigned_at,omitempty"`
+	CompletedAt int64           `json:"completed_at,omitempty"`
+	Links       []*ChainLink    `json:"links"`
+	CreatedAt   int64           `json:"created_at"`
+}
+
+type ChainLink struct {
+	Position  int            `json:"position"`
+	Address   common.Address `json:"address"`
+	Line      string         `json:"line"`
+	CreatedAt int64          `json:"created_at"`
+}
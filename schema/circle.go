@@ -0,0 +1,30 @@
+package schema
+
+import "github.com/ethereum/go-ethereum/common"
+
+const (
+	CircleMemberStatusInvited = "invited"
+	CircleMemberStatusJoined  = "joined"
+)
+
+type Circle struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Owner     common.Address `json:"owner"`
+	CreatedAt int64          `json:"created_at"`
+}
+
+type CircleMember struct {
+	CircleID  string         `json:"circle_id"`
+	Address   common.Address `json:"address"`
+	Status    string         `json:"status"`
+	InvitedBy common.Address `json:"invited_by"`
+	JoinedAt  int64          `json:"joined_at,omitempty"`
+	CreatedAt int64          `json:"created_at"`
+}
+
+// CircleMembership is a circle seen from one of its members or invitees.
+type CircleMembership struct {
+	Circle *Circle `json:"circle"`
+	Status string  `json:"status"`
+}
@@ -0,0 +1,31 @@
+package schema
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// EncryptionKey is the uncompressed secp256k1 public key notes to an address are encrypted to.
+type EncryptionKey struct {
+	Address   common.Address `json:"address"`
+	PublicKey hexutil.Bytes  `json:"public_key"`
+	UpdatedAt int64          `json:"updated_at"`
+}
+
+// EncryptedNote is an ECIES envelope only its recipient can open, the hub never sees the plain text.
+type EncryptedNote struct {
+	ID        uint64         `json:"id"`
+	MessageID string         `json:"message_id"`
+	Address   common.Address `json:"address"`
+	Recipient common.Address `json:"recipient"`
+	// PublicKey is the key of the recipient the note was encrypted to, it tells clients which key opens it after a rotation.
+	PublicKey  hexutil.Bytes `json:"public_key"`
+	Ciphertext hexutil.Bytes `json:"ciphertext"`
+	CreatedAt  int64         `json:"created_at"`
+}
+
+type EncryptedNoteQuery struct {
+	Recipient common.Address
+	Cursor    *uint64
+	Limit     int
+}
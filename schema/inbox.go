@@ -0,0 +1,28 @@
+package schema
+
+import "github.com/ethereum/go-ethereum/common"
+
+const (
+	InboxTypeReply     = "reply"
+	InboxTypeAnswered  = "answered"
+	InboxTypeDirected  = "directed"
+	InboxTypeEncrypted = "encrypted"
+)
+
+type InboxItem struct {
+	ID        uint64         `json:"id"`
+	Address   common.Address `json:"-"`
+	Type      string         `json:"type"`
+	NoteID    string         `json:"note_id"`
+	ReplyID   *uint64        `json:"reply_id,omitempty"`
+	Actor     common.Address `json:"actor"`
+	Read      bool           `json:"read"`
+	CreatedAt int64          `json:"created_at"`
+}
+
+type InboxQuery struct {
+	Address    common.Address
+	UnreadOnly bool
+	Cursor     *uint64
+	Limit      int
+}
@@ -0,0 +1,24 @@
+package schema
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PendingMint is a reward whose mint failed, it is retried until it succeeds or runs out of attempts.
+type PendingMint struct {
+	ID      uint64         `json:"id"`
+	Address common.Address `json:"address"`
+	Amount  *big.Int       `json:"amount"`
+	// Reason names what the reward is for, such as answer:<note id>.
+	Reason    string `json:"reason"`
+	Attempts  int    `json:"attempts"`
+	LastError string `json:"last_error,omitempty"`
+	// TransactionHash is the transaction that minted the reward.
+	TransactionHash *common.Hash `json:"transaction_hash,omitempty"`
+	// TransactionHashes holds every mint transaction sent for the reward, oldest first.
+	TransactionHashes []common.Hash `json:"transaction_hashes,omitempty"`
+	MintedAt          int64         `json:"minted_at,omitempty"`
+	CreatedAt         int64         `json:"created_at"`
+}
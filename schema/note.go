@@ -2,9 +2,64 @@ package schema
 
 import "github.com/ethereum/go-ethereum/common"
 
+// A chain prayer is a note written line by line by several contributors, it is only readable once completed.
+const (
+	NoteKindNote  = "note"
+	NoteKindChain = "chain"
+)
+
 type Note struct {
-	ID        uint64         `json:"id"`
-	Address   common.Address `json:"address"`
-	Note      string         `json:"note"`
-	CreatedAt int64          `json:"created_at"`
+	ID            uint64         `json:"id"`
+	MessageID     string         `json:"message_id"`
+	Address       common.Address `json:"address"`
+	Note          string         `json:"note"`
+	Category      string         `json:"category,omitempty"`
+	Tags          []string       `json:"tags,omitempty"`
+	Language      string         `json:"language,omitempty"`
+	Hidden        bool           `json:"hidden"`
+	Anonymous     bool           `json:"anonymous"`
+	RevealAt      int64          `json:"reveal_at,omitempty"`
+	ExpiresAt     int64          `json:"expires_at,omitempty"`
+	ArchivedAt    int64          `json:"archived_at,omitempty"`
+	EditedAt      int64          `json:"edited_at,omitempty"`
+	AnsweredAt    int64          `json:"answered_at,omitempty"`
+	Testimony     string         `json:"testimony,omitempty"`
+	Intercessions int64          `json:"intercessions"`
+	Kind          string         `json:"kind"`
+	CreatedAt     int64          `json:"created_at"`
+
+	// Circle is the private circle the note was shared with, empty for public notes.
+	Circle string `json:"circle,omitempty"`
+
+	// Recipient is the address the note was directed to, it is offered to them before any other note.
+	Recipient *common.Address `json:"recipient,omitempty"`
+
+	// SearchLanguage is the postgres text search configuration the note is indexed with.
+	SearchLanguage string `json:"-"`
+}
+
+type NoteQuery struct {
+	Address  *common.Address
+	Archived bool
+	// Sealed lists time capsules that are not revealed yet instead of readable notes.
+	Sealed bool
+	Cursor *uint64
+	Limit  int
+}
+
+type AnsweredNoteQuery struct {
+	Offset int
+	Limit  int
+}
+
+type NoteSearchQuery struct {
+	Query    string
+	Language string
+	Offset   int
+	Limit    int
+}
+
+type NoteSearchResult struct {
+	Note *Note
+	Rank float64
 }
@@ -0,0 +1,9 @@
+package schema
+
+// NoteRevision keeps the text a note had before one of its edits.
+type NoteRevision struct {
+	ID        uint64 `json:"id"`
+	NoteID    string `json:"note_id"`
+	Note      string `json:"note"`
+	CreatedAt int64  `json:"created_at"`
+}
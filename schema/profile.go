@@ -0,0 +1,12 @@
+package schema
+
+import "github.com/ethereum/go-ethereum/common"
+
+type Profile struct {
+	Address     common.Address `json:"address"`
+	DisplayName string         `json:"display_name"`
+	Bio         string         `json:"bio"`
+	// Avatar is either one of the configured preset names or the path of an uploaded image.
+	Avatar    string `json:"avatar"`
+	UpdatedAt int64  `json:"updated_at"`
+}
@@ -0,0 +1,34 @@
+package schema
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	ReactionTypeAmen  = "amen"
+	ReactionTypePray  = "pray"
+	ReactionTypeHeart = "heart"
+	ReactionTypeHug   = "hug"
+)
+
+var ReactionTypes = []string{ReactionTypeAmen, ReactionTypePray, ReactionTypeHeart, ReactionTypeHug}
+
+type Reaction struct {
+	ID        uint64         `json:"id"`
+	NoteID    string         `json:"note_id"`
+	Address   common.Address `json:"address"`
+	Type      string         `json:"type"`
+	CreatedAt int64          `json:"created_at"`
+}
+
+type Tip struct {
+	ID              uint64         `json:"id"`
+	TransactionHash common.Hash    `json:"transaction_hash"`
+	NoteID          string         `json:"note_id"`
+	From            common.Address `json:"from"`
+	To              common.Address `json:"to"`
+	Amount          *big.Int       `json:"amount"`
+	CreatedAt       int64          `json:"created_at"`
+}
@@ -0,0 +1,20 @@
+package schema
+
+import "github.com/ethereum/go-ethereum/common"
+
+type Reply struct {
+	ID        uint64         `json:"id"`
+	NoteID    string         `json:"note_id"`
+	Address   common.Address `json:"address"`
+	Reply     string         `json:"reply"`
+	Anonymous bool           `json:"anonymous"`
+	// Verified marks a reply signed by its address after the note was handed out to it.
+	Verified  bool  `json:"verified"`
+	CreatedAt int64 `json:"created_at"`
+}
+
+type ReplyQuery struct {
+	NoteID string
+	Cursor *uint64
+	Limit  int
+}